@@ -0,0 +1,187 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MachineDetection is the kind of answering machine detection to perform
+// when a call is answered.
+type MachineDetection string
+
+// The kinds of answering machine detection.
+const (
+	// MachineDetectionEnable reports whether a human or a machine answered
+	// as soon as it is known.
+	MachineDetectionEnable MachineDetection = "Enable"
+
+	// MachineDetectionDetectMessageEnd waits for a machine's greeting to end,
+	// so that a voicemail can be left after the beep.
+	MachineDetectionDetectMessageEnd MachineDetection = "DetectMessageEnd"
+)
+
+// AnsweredBy is the result of answering machine detection.
+type AnsweredBy string
+
+// The results of answering machine detection.
+const (
+	AnsweredByHuman             AnsweredBy = "human"
+	AnsweredByFax               AnsweredBy = "fax"
+	AnsweredByUnknown           AnsweredBy = "unknown"
+	AnsweredByMachineStart      AnsweredBy = "machine_start"
+	AnsweredByMachineEndBeep    AnsweredBy = "machine_end_beep"
+	AnsweredByMachineEndSilence AnsweredBy = "machine_end_silence"
+	AnsweredByMachineEndOther   AnsweredBy = "machine_end_other"
+)
+
+// Machine returns true when a machine answered the call.
+func (answeredBy AnsweredBy) Machine() bool {
+	switch answeredBy {
+	case AnsweredByMachineStart,
+		AnsweredByMachineEndBeep,
+		AnsweredByMachineEndSilence,
+		AnsweredByMachineEndOther:
+		return true
+	}
+
+	return false
+}
+
+// MessageEnded returns true when a machine's greeting has ended, which is
+// only reported with MachineDetectionDetectMessageEnd.
+func (answeredBy AnsweredBy) MessageEnded() bool {
+	switch answeredBy {
+	case AnsweredByMachineEndBeep,
+		AnsweredByMachineEndSilence,
+		AnsweredByMachineEndOther:
+		return true
+	}
+
+	return false
+}
+
+// AMDEvent is the result of asynchronous answering machine detection, as
+// sent by Twilio to a call's AsyncAMDStatusCallback.
+type AMDEvent struct {
+	CallSID    string
+	AccountSID string
+	AnsweredBy AnsweredBy
+
+	// MachineDetectionDuration is how long detection took in milliseconds.
+	MachineDetectionDuration int
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseAMDEvent parses the parameters of an asynchronous answering machine
+// detection callback.
+func ParseAMDEvent(form url.Values) (*AMDEvent, error) {
+	event := &AMDEvent{
+		CallSID:    form.Get("CallSid"),
+		AccountSID: form.Get("AccountSid"),
+		AnsweredBy: AnsweredBy(form.Get("AnsweredBy")),
+		Form:       form,
+	}
+
+	if event.CallSID == "" {
+		return nil, fmt.Errorf("Missing parameter: CallSid")
+	}
+
+	if event.AnsweredBy == "" {
+		return nil, fmt.Errorf("Missing parameter: AnsweredBy")
+	}
+
+	var err error
+	event.MachineDetectionDuration, err = parseIntParam(form, "MachineDetectionDuration")
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// AMDHandlerFunc decides what to do with a call once answering machine
+// detection is complete. When it returns update parameters, such as TwiML
+// that leaves a voicemail, the call is updated with them. When it returns
+// nil, the call continues unchanged.
+type AMDHandlerFunc func(ctx context.Context, event *AMDEvent) (*CallUpdateParams, error)
+
+// AMDHandler is an http.Handler for asynchronous answering machine detection
+// callbacks. It ignores callbacks that have already been handled, so that a
+// retried callback does not update the call again. Concurrent deliveries of
+// the same callback wait for the first to be handled.
+type AMDHandler struct {
+	validator *RequestValidator
+	client    Client
+	handler   AMDHandlerFunc
+
+	// DedupeWindow is how long a handled callback is remembered so that
+	// repeated callbacks for it are ignored.
+	DedupeWindow time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	deduper *WebhookDeduper
+}
+
+// NewAMDHandler will create a handler that validates requests with the
+// validator, handles them with the function and updates calls using the
+// client. Requests are not validated when the validator is nil.
+func NewAMDHandler(
+	validator *RequestValidator,
+	client Client,
+	handler AMDHandlerFunc,
+) *AMDHandler {
+	h := &AMDHandler{
+		validator:    validator,
+		client:       client,
+		handler:      handler,
+		DedupeWindow: 24 * time.Hour,
+		Now:          time.Now,
+	}
+
+	now := func() time.Time { return h.Now() }
+	store := NewMemoryWebhookDedupeStore()
+	store.Now = now
+	h.deduper = NewWebhookDeduper(store)
+	h.deduper.Now = now
+	return h
+}
+
+func (h *AMDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	event, err := ParseAMDEvent(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := event.CallSID + "/" + string(event.AnsweredBy)
+	h.deduper.serve(w, key, h.DedupeWindow, func(w http.ResponseWriter) {
+		params, err := h.handler(r.Context(), event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if params != nil {
+			_, err = h.client.UpdateCall(event.CallSID, params)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
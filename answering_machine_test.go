@@ -0,0 +1,103 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnsweredBy(t *testing.T) {
+	assert.False(t, AnsweredByHuman.Machine())
+	assert.False(t, AnsweredByFax.Machine())
+	assert.True(t, AnsweredByMachineStart.Machine())
+	assert.False(t, AnsweredByMachineStart.MessageEnded())
+	assert.True(t, AnsweredByMachineEndBeep.Machine())
+	assert.True(t, AnsweredByMachineEndBeep.MessageEnded())
+}
+
+func TestCallParamsWithAsyncAMD(t *testing.T) {
+	async := true
+	v, err := (&CallParams{
+		From:                               "+14155552345",
+		To:                                 "+15108675310",
+		URL:                                "https://example.com/voice",
+		MachineDetection:                   MachineDetectionDetectMessageEnd,
+		MachineDetectionSpeechEndThreshold: 1200,
+		AsyncAMD:                           &async,
+		AsyncAMDStatusCallback:             "https://example.com/amd",
+	}).values()
+
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"From":                               {"+14155552345"},
+		"To":                                 {"+15108675310"},
+		"Url":                                {"https://example.com/voice"},
+		"MachineDetection":                   {"DetectMessageEnd"},
+		"MachineDetectionSpeechEndThreshold": {"1200"},
+		"AsyncAmd":                           {"true"},
+		"AsyncAmdStatusCallback":             {"https://example.com/amd"},
+	}, v)
+}
+
+func TestParseAMDEvent(t *testing.T) {
+	event, err := ParseAMDEvent(url.Values{
+		"CallSid":                  {"CA1"},
+		"AnsweredBy":               {"machine_end_beep"},
+		"MachineDetectionDuration": {"4500"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, AnsweredByMachineEndBeep, event.AnsweredBy)
+	assert.Equal(t, 4500, event.MachineDetectionDuration)
+
+	_, err = ParseAMDEvent(url.Values{"CallSid": {"CA1"}})
+	assert.EqualError(t, err, "Missing parameter: AnsweredBy")
+}
+
+func TestAMDHandlerUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	updates := 0
+	mux.HandleFunc("/Accounts/sid/Calls/CA1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{"Url": {"https://example.com/voicemail"}}, r.PostForm)
+		updates++
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "CA1"}`))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	handler := NewAMDHandler(NewRequestValidator("token"), client, func(ctx context.Context, event *AMDEvent) (*CallUpdateParams, error) {
+		if !event.AnsweredBy.MessageEnded() {
+			return nil, nil
+		}
+
+		return &CallUpdateParams{URL: "https://example.com/voicemail"}, nil
+	})
+
+	send := func(answeredBy string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/amd", url.Values{
+			"CallSid":    {"CA1"},
+			"AnsweredBy": {answeredBy},
+		}))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("human"))
+	assert.Equal(t, 0, updates)
+
+	assert.Equal(t, http.StatusNoContent, send("machine_end_beep"))
+	assert.Equal(t, 1, updates)
+
+	assert.Equal(t, http.StatusNoContent, send("machine_end_beep"))
+	assert.Equal(t, 1, updates)
+}
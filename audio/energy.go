@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// MinLevel is the level of digital silence in dBFS.
+const MinLevel = -96.0
+
+// RMS returns the root mean square of the samples, between 0 and 32768.
+func RMS(samples []int16) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+// Level returns the loudness of the samples in dBFS, from MinLevel for
+// silence to 0 for a full scale square wave.
+func Level(samples []int16) float64 {
+	rms := RMS(samples)
+	if rms == 0 {
+		return MinLevel
+	}
+
+	level := 20 * math.Log10(rms/32768)
+	if level < MinLevel {
+		return MinLevel
+	}
+
+	return level
+}
+
+// SilenceDetector detects when a stream of audio has been silent for a
+// minimum duration, such as when a caller stops speaking.
+type SilenceDetector struct {
+	// Threshold is the level in dBFS below which audio is silent.
+	Threshold float64
+
+	// MinDuration is how long audio must be silent before it is detected.
+	MinDuration time.Duration
+
+	sampleRate int
+	silentFor  int
+}
+
+// NewSilenceDetector will create a detector for audio at the sample rate
+// that detects 500ms below -45 dBFS.
+func NewSilenceDetector(sampleRate int) *SilenceDetector {
+	return &SilenceDetector{
+		Threshold:   -45,
+		MinDuration: 500 * time.Millisecond,
+		sampleRate:  sampleRate,
+	}
+}
+
+// Process adds the next chunk of audio, such as a 20ms Media Streams
+// payload, and returns true when the audio has been silent for at least the
+// minimum duration.
+func (d *SilenceDetector) Process(samples []int16) bool {
+	if Level(samples) < d.Threshold {
+		d.silentFor += len(samples)
+	} else {
+		d.silentFor = 0
+	}
+
+	return d.Silence() >= d.MinDuration
+}
+
+// Silence returns how long the audio has been silent.
+func (d *SilenceDetector) Silence() time.Duration {
+	if d.sampleRate <= 0 {
+		return 0
+	}
+
+	return time.Duration(d.silentFor) * time.Second / time.Duration(d.sampleRate)
+}
+
+// Reset forgets the audio processed so far.
+func (d *SilenceDetector) Reset() {
+	d.silentFor = 0
+}
@@ -0,0 +1,39 @@
+// +build unit
+
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, MinLevel, Level(nil))
+	assert.Equal(t, MinLevel, Level(make([]int16, 160)))
+	assert.InDelta(t, 0, Level([]int16{32767, -32768}), 0.01)
+	assert.InDelta(t, -13.3, Level(sine(440, Rate8kHz, 800)), 0.1)
+}
+
+func TestSilenceDetector(t *testing.T) {
+	d := NewSilenceDetector(Rate8kHz)
+	speech := sine(440, Rate8kHz, 160)
+	quiet := make([]int16, 160)
+	for i := range quiet {
+		quiet[i] = int16(i%3 - 1)
+	}
+
+	assert.False(t, d.Process(speech))
+	for i := 0; i < 24; i++ {
+		assert.False(t, d.Process(quiet))
+	}
+	assert.Equal(t, 480*time.Millisecond, d.Silence())
+
+	assert.True(t, d.Process(quiet))
+	assert.False(t, d.Process(speech))
+
+	d.Process(quiet)
+	d.Reset()
+	assert.Equal(t, time.Duration(0), d.Silence())
+}
@@ -0,0 +1,86 @@
+// Package audio converts and analyzes the audio used by Twilio, such as the
+// 8 kHz μ-law audio of Media Streams and the WAV files of recordings. Audio
+// is mono unless a function says otherwise.
+package audio
+
+const (
+	mulawBias = 0x84
+	mulawClip = 32635
+)
+
+// The sample rates used by Twilio and common speech services.
+const (
+	Rate8kHz  = 8000
+	Rate16kHz = 16000
+	Rate24kHz = 24000
+)
+
+var mulawTable [256]int16
+
+func init() {
+	for i := range mulawTable {
+		mulawTable[i] = mulawToLinear(byte(i))
+	}
+}
+
+// MulawToLinear converts a G.711 μ-law sample to 16-bit linear PCM.
+func MulawToLinear(b byte) int16 {
+	return mulawTable[b]
+}
+
+// LinearToMulaw converts a 16-bit linear PCM sample to G.711 μ-law.
+func LinearToMulaw(sample int16) byte {
+	s := int(sample)
+	var sign int
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+
+	if s > mulawClip {
+		s = mulawClip
+	}
+
+	s += mulawBias
+	exponent := 7
+	for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+
+	mantissa := (s >> uint(exponent+3)) & 0x0f
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+// DecodeMulaw converts μ-law audio, such as a Media Streams payload, to
+// 16-bit linear PCM.
+func DecodeMulaw(data []byte) []int16 {
+	samples := make([]int16, len(data))
+	for i, b := range data {
+		samples[i] = mulawTable[b]
+	}
+
+	return samples
+}
+
+// EncodeMulaw converts 16-bit linear PCM to μ-law audio.
+func EncodeMulaw(samples []int16) []byte {
+	data := make([]byte, len(samples))
+	for i, s := range samples {
+		data[i] = LinearToMulaw(s)
+	}
+
+	return data
+}
+
+func mulawToLinear(b byte) int16 {
+	b = ^b
+	exponent := uint(b>>4) & 0x07
+	mantissa := int(b & 0x0f)
+	s := ((mantissa << 3) + mulawBias) << exponent
+	s -= mulawBias
+	if b&0x80 != 0 {
+		s = -s
+	}
+
+	return int16(s)
+}
@@ -0,0 +1,47 @@
+// +build unit
+
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMulawToLinear(t *testing.T) {
+	assert.Equal(t, int16(0), MulawToLinear(0xff))
+	assert.Equal(t, int16(0), MulawToLinear(0x7f))
+	assert.Equal(t, int16(-32124), MulawToLinear(0x00))
+	assert.Equal(t, int16(32124), MulawToLinear(0x80))
+}
+
+func TestLinearToMulaw(t *testing.T) {
+	assert.Equal(t, byte(0xff), LinearToMulaw(0))
+	assert.Equal(t, byte(0x80), LinearToMulaw(32767))
+	assert.Equal(t, byte(0x00), LinearToMulaw(-32768))
+
+	// Every μ-law value survives a round trip, except negative zero.
+	for i := 0; i < 256; i++ {
+		if i == 0x7f {
+			continue
+		}
+
+		assert.Equal(t, byte(i), LinearToMulaw(MulawToLinear(byte(i))))
+	}
+}
+
+func TestEncodeAndDecodeMulaw(t *testing.T) {
+	samples := []int16{0, 1000, -1000, 8000, -8000}
+	decoded := DecodeMulaw(EncodeMulaw(samples))
+	for i, s := range samples {
+		assert.InDelta(t, s, decoded[i], float64(abs(s))/16+8)
+	}
+}
+
+func abs(s int16) int16 {
+	if s < 0 {
+		return -s
+	}
+
+	return s
+}
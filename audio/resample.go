@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidSampleRate is returned when a sample rate is not positive.
+var ErrInvalidSampleRate = errors.New("Invalid sample rate")
+
+// Resample converts audio from one sample rate to another using linear
+// interpolation. When the rate is reduced, the audio is first low-pass
+// filtered to reduce aliasing.
+func Resample(samples []int16, from int, to int) ([]int16, error) {
+	if from <= 0 || to <= 0 {
+		return nil, ErrInvalidSampleRate
+	}
+
+	if from == to || len(samples) == 0 {
+		return append([]int16{}, samples...), nil
+	}
+
+	input := samples
+	if to < from {
+		input = lowPass(samples, (from+to-1)/to)
+	}
+
+	out := make([]int16, int(int64(len(samples))*int64(to)/int64(from)))
+	step := float64(from) / float64(to)
+	last := len(input) - 1
+	for i := range out {
+		pos := float64(i) * step
+		j := int(pos)
+		if j >= last {
+			out[i] = input[last]
+			continue
+		}
+
+		frac := pos - float64(j)
+		out[i] = int16(math.Round(float64(input[j])*(1-frac) + float64(input[j+1])*frac))
+	}
+
+	return out, nil
+}
+
+// lowPass averages each sample with its neighbours in a window of the width.
+func lowPass(samples []int16, width int) []int16 {
+	if width <= 1 {
+		return samples
+	}
+
+	sums := make([]int64, len(samples)+1)
+	for i, s := range samples {
+		sums[i+1] = sums[i] + int64(s)
+	}
+
+	out := make([]int16, len(samples))
+	for i := range samples {
+		start := i - width/2
+		if start < 0 {
+			start = 0
+		}
+
+		end := start + width
+		if end > len(samples) {
+			end = len(samples)
+		}
+
+		out[i] = int16((sums[end] - sums[start]) / int64(end-start))
+	}
+
+	return out
+}
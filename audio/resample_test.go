@@ -0,0 +1,50 @@
+// +build unit
+
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sine(freq float64, rate int, n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+
+	return samples
+}
+
+func TestResample(t *testing.T) {
+	for _, rates := range [][2]int{
+		{Rate8kHz, Rate16kHz},
+		{Rate16kHz, Rate8kHz},
+		{Rate8kHz, Rate24kHz},
+		{Rate24kHz, Rate8kHz},
+		{Rate16kHz, Rate24kHz},
+	} {
+		from, to := rates[0], rates[1]
+		out, err := Resample(sine(440, from, from), from, to)
+		assert.NoError(t, err)
+		assert.Len(t, out, to)
+
+		// A low tone keeps its shape and most of its level.
+		expected := sine(440, to, to)
+		for i := to / 4; i < to*3/4; i += 97 {
+			assert.InDelta(t, expected[i], out[i], 1500, "%d to %d at %d", from, to, i)
+		}
+	}
+}
+
+func TestResampleSameRateAndErrors(t *testing.T) {
+	samples := []int16{1, 2, 3}
+	out, err := Resample(samples, Rate8kHz, Rate8kHz)
+	assert.NoError(t, err)
+	assert.Equal(t, samples, out)
+
+	_, err = Resample(samples, 0, Rate8kHz)
+	assert.Equal(t, ErrInvalidSampleRate, err)
+}
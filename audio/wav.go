@@ -0,0 +1,230 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// Format is the encoding of a WAV file's samples.
+type Format uint16
+
+// The WAV formats used by Twilio.
+const (
+	FormatPCM   Format = 1
+	FormatMulaw Format = 7
+)
+
+const formatExtensible = 0xfffe
+
+var (
+	// ErrInvalidWAV is returned when reading a file that is not a WAV file.
+	ErrInvalidWAV = errors.New("Invalid WAV file")
+
+	// ErrUnsupportedWAV is returned when a WAV file's samples cannot be
+	// decoded.
+	ErrUnsupportedWAV = errors.New("Unsupported WAV format")
+)
+
+// WAV is the audio in a WAV file. Data contains the samples as they are
+// stored in the file, interleaved when there is more than one channel.
+type WAV struct {
+	Format        Format
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+	Data          []byte
+}
+
+// NewPCMWAV will create a 16-bit linear PCM WAV from the samples.
+func NewPCMWAV(samples []int16, sampleRate int, channels int) *WAV {
+	data := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(s))
+	}
+
+	return &WAV{
+		Format:        FormatPCM,
+		SampleRate:    sampleRate,
+		Channels:      channels,
+		BitsPerSample: 16,
+		Data:          data,
+	}
+}
+
+// NewMulawWAV will create a mono μ-law WAV from μ-law audio, such as the
+// payloads of a Media Stream.
+func NewMulawWAV(data []byte, sampleRate int) *WAV {
+	return &WAV{
+		Format:        FormatMulaw,
+		SampleRate:    sampleRate,
+		Channels:      1,
+		BitsPerSample: 8,
+		Data:          data,
+	}
+}
+
+// Samples returns the audio as 16-bit linear PCM, interleaved when there is
+// more than one channel.
+func (wav *WAV) Samples() ([]int16, error) {
+	switch {
+	case wav.Format == FormatPCM && wav.BitsPerSample == 16:
+		samples := make([]int16, len(wav.Data)/2)
+		for i := range samples {
+			samples[i] = int16(binary.LittleEndian.Uint16(wav.Data[2*i:]))
+		}
+
+		return samples, nil
+
+	case wav.Format == FormatPCM && wav.BitsPerSample == 8:
+		samples := make([]int16, len(wav.Data))
+		for i, b := range wav.Data {
+			samples[i] = int16(int(b)-128) << 8
+		}
+
+		return samples, nil
+
+	case wav.Format == FormatMulaw && wav.BitsPerSample == 8:
+		return DecodeMulaw(wav.Data), nil
+	}
+
+	return nil, ErrUnsupportedWAV
+}
+
+// Duration returns the length of the audio in seconds.
+func (wav *WAV) Duration() float64 {
+	frameSize := wav.Channels * wav.BitsPerSample / 8
+	if frameSize == 0 || wav.SampleRate == 0 {
+		return 0
+	}
+
+	return float64(len(wav.Data)/frameSize) / float64(wav.SampleRate)
+}
+
+// ReadWAV reads a WAV file, such as a downloaded recording. Chunks other
+// than the format and data are skipped. A data chunk whose size is larger
+// than the file, as written by streaming encoders, is read to the end.
+func ReadWAV(r io.Reader) (*WAV, error) {
+	var header [12]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil || string(header[:4]) != "RIFF" || string(header[8:]) != "WAVE" {
+		return nil, ErrInvalidWAV
+	}
+
+	var wav *WAV
+	for {
+		var chunk [8]byte
+		_, err = io.ReadFull(r, chunk[:])
+		if err != nil {
+			return nil, ErrInvalidWAV
+		}
+
+		id := string(chunk[:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		switch id {
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return nil, ErrInvalidWAV
+			}
+
+			b := make([]byte, size+size%2)
+			_, err = io.ReadFull(r, b)
+			if err != nil {
+				return nil, ErrInvalidWAV
+			}
+
+			wav = &WAV{
+				Format:        Format(binary.LittleEndian.Uint16(b[0:])),
+				Channels:      int(binary.LittleEndian.Uint16(b[2:])),
+				SampleRate:    int(binary.LittleEndian.Uint32(b[4:])),
+				BitsPerSample: int(binary.LittleEndian.Uint16(b[14:])),
+			}
+
+			if wav.Format == formatExtensible && size >= 40 {
+				wav.Format = Format(binary.LittleEndian.Uint16(b[24:]))
+			}
+
+		case "data":
+			if wav == nil {
+				return nil, ErrInvalidWAV
+			}
+
+			wav.Data, err = ioutil.ReadAll(io.LimitReader(r, size))
+			if err != nil {
+				return nil, err
+			}
+
+			return wav, nil
+
+		default:
+			_, err = io.CopyN(ioutil.Discard, r, size+size%2)
+			if err != nil {
+				return nil, ErrInvalidWAV
+			}
+		}
+	}
+}
+
+// WriteWAV writes the audio as a WAV file.
+func WriteWAV(w io.Writer, wav *WAV) error {
+	blockAlign := wav.Channels * wav.BitsPerSample / 8
+	fmtSize := 16
+	if wav.Format != FormatPCM {
+		fmtSize = 18
+	}
+
+	pad := len(wav.Data) % 2
+	riffSize := 4 + 8 + fmtSize + 8 + len(wav.Data) + pad
+	if wav.Format != FormatPCM {
+		riffSize += 12
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(riffSize))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(fmtSize))
+	binary.Write(buf, binary.LittleEndian, uint16(wav.Format))
+	binary.Write(buf, binary.LittleEndian, uint16(wav.Channels))
+	binary.Write(buf, binary.LittleEndian, uint32(wav.SampleRate))
+	binary.Write(buf, binary.LittleEndian, uint32(wav.SampleRate*blockAlign))
+	binary.Write(buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(buf, binary.LittleEndian, uint16(wav.BitsPerSample))
+
+	// Formats other than PCM have an extension size and a fact chunk with
+	// the number of samples per channel.
+	if wav.Format != FormatPCM {
+		binary.Write(buf, binary.LittleEndian, uint16(0))
+		buf.WriteString("fact")
+		binary.Write(buf, binary.LittleEndian, uint32(4))
+
+		var frames int
+		if blockAlign > 0 {
+			frames = len(wav.Data) / blockAlign
+		}
+
+		binary.Write(buf, binary.LittleEndian, uint32(frames))
+	}
+
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(len(wav.Data)))
+	_, err := w.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(wav.Data)
+	if err != nil {
+		return err
+	}
+
+	if pad != 0 {
+		_, err = w.Write([]byte{0})
+	}
+
+	return err
+}
@@ -0,0 +1,69 @@
+// +build unit
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAndReadPCMWAV(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteWAV(buf, NewPCMWAV(samples, Rate8kHz, 1)))
+	assert.Equal(t, 44+10, buf.Len())
+	assert.Equal(t, "RIFF", buf.String()[:4])
+	assert.Equal(t, uint32(buf.Len()-8), binary.LittleEndian.Uint32(buf.Bytes()[4:]))
+
+	wav, err := ReadWAV(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPCM, wav.Format)
+	assert.Equal(t, Rate8kHz, wav.SampleRate)
+	assert.Equal(t, 1, wav.Channels)
+	assert.Equal(t, 16, wav.BitsPerSample)
+
+	decoded, err := wav.Samples()
+	assert.NoError(t, err)
+	assert.Equal(t, samples, decoded)
+}
+
+func TestWriteAndReadMulawWAV(t *testing.T) {
+	data := []byte{0xff, 0x80, 0x00}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteWAV(buf, NewMulawWAV(data, Rate8kHz)))
+	assert.Equal(t, 0, buf.Len()%2)
+
+	wav, err := ReadWAV(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMulaw, wav.Format)
+	assert.Equal(t, data, wav.Data)
+	assert.InDelta(t, 3.0/8000, wav.Duration(), 1e-9)
+
+	samples, err := wav.Samples()
+	assert.NoError(t, err)
+	assert.Equal(t, []int16{0, 32124, -32124}, samples)
+}
+
+func TestReadWAVSkipsChunks(t *testing.T) {
+	pcm := &bytes.Buffer{}
+	assert.NoError(t, WriteWAV(pcm, NewPCMWAV([]int16{7}, Rate16kHz, 1)))
+
+	// Insert a LIST chunk with an odd size between the format and data.
+	b := pcm.Bytes()
+	list := []byte{'L', 'I', 'S', 'T', 3, 0, 0, 0, 'a', 'b', 'c', 0}
+	withList := append(append(append([]byte{}, b[:36]...), list...), b[36:]...)
+
+	wav, err := ReadWAV(bytes.NewReader(withList))
+	assert.NoError(t, err)
+	assert.Equal(t, Rate16kHz, wav.SampleRate)
+	assert.Equal(t, []byte{7, 0}, wav.Data)
+
+	_, err = ReadWAV(bytes.NewReader([]byte("not a wav file")))
+	assert.Equal(t, ErrInvalidWAV, err)
+
+	_, err = (&WAV{Format: FormatPCM, BitsPerSample: 24}).Samples()
+	assert.Equal(t, ErrUnsupportedWAV, err)
+}
@@ -0,0 +1,179 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CallStatusEvent is a change to the status of a call, as sent by Twilio to
+// a call's status callback.
+type CallStatusEvent struct {
+	CallSID       string
+	AccountSID    string
+	ParentCallSID string
+	From          string
+	To            string
+	CallStatus    CallStatus
+	Direction     CallDirection
+
+	// CallDuration is the duration of a completed call in seconds.
+	CallDuration int
+
+	// SequenceNumber orders the events of a call, starting at 0.
+	SequenceNumber int
+
+	// Timestamp is when the event occurred.
+	Timestamp time.Time
+
+	// CallbackSource is call-progress-events for status callbacks.
+	CallbackSource string
+
+	// SIPResponseCode is the SIP response code of a failed call.
+	SIPResponseCode int
+
+	// AnsweredBy is set when answering machine detection is enabled.
+	AnsweredBy AnsweredBy
+
+	RecordingURL      string
+	RecordingSID      string
+	RecordingDuration int
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseCallStatusEvent parses the parameters of a call status callback.
+func ParseCallStatusEvent(form url.Values) (*CallStatusEvent, error) {
+	event := &CallStatusEvent{
+		CallSID:        form.Get("CallSid"),
+		AccountSID:     form.Get("AccountSid"),
+		ParentCallSID:  form.Get("ParentCallSid"),
+		From:           form.Get("From"),
+		To:             form.Get("To"),
+		CallStatus:     CallStatus(form.Get("CallStatus")),
+		Direction:      CallDirection(form.Get("Direction")),
+		CallbackSource: form.Get("CallbackSource"),
+		AnsweredBy:     AnsweredBy(form.Get("AnsweredBy")),
+		RecordingURL:   form.Get("RecordingUrl"),
+		RecordingSID:   form.Get("RecordingSid"),
+		Form:           form,
+	}
+
+	if event.CallSID == "" {
+		return nil, fmt.Errorf("Missing parameter: CallSid")
+	}
+
+	if event.CallStatus == "" {
+		return nil, fmt.Errorf("Missing parameter: CallStatus")
+	}
+
+	for _, param := range []struct {
+		key  string
+		dest *int
+	}{
+		{"CallDuration", &event.CallDuration},
+		{"SequenceNumber", &event.SequenceNumber},
+		{"SipResponseCode", &event.SIPResponseCode},
+		{"RecordingDuration", &event.RecordingDuration},
+	} {
+		var err error
+		*param.dest, err = parseIntParam(form, param.key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if timestamp := form.Get("Timestamp"); timestamp != "" {
+		var err error
+		event.Timestamp, err = time.Parse(time.RFC1123Z, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid parameter: Timestamp")
+		}
+	}
+
+	return event, nil
+}
+
+// CallStatusListener is called for each new call status event. When a
+// listener returns an error, Twilio is asked to retry the callback.
+type CallStatusListener func(ctx context.Context, event *CallStatusEvent) error
+
+// CallStatusHandler is an http.Handler for call status callbacks. It
+// validates the request's signature, parses the event, ignores events that
+// have already been handled and dispatches new events to its listeners.
+// Concurrent deliveries of the same event wait for the first to be handled.
+type CallStatusHandler struct {
+	validator *RequestValidator
+
+	// DedupeWindow is how long a handled event is remembered so that
+	// repeated callbacks for it are ignored.
+	DedupeWindow time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	listeners []CallStatusListener
+	deduper   *WebhookDeduper
+}
+
+// NewCallStatusHandler will create a handler that validates requests with the
+// validator. Requests are not validated when the validator is nil.
+func NewCallStatusHandler(validator *RequestValidator) *CallStatusHandler {
+	h := &CallStatusHandler{
+		validator:    validator,
+		DedupeWindow: 24 * time.Hour,
+		Now:          time.Now,
+	}
+
+	now := func() time.Time { return h.Now() }
+	store := NewMemoryWebhookDedupeStore()
+	store.Now = now
+	h.deduper = NewWebhookDeduper(store)
+	h.deduper.Now = now
+	return h
+}
+
+// AddListener registers a listener for new events.
+func (h *CallStatusHandler) AddListener(listener CallStatusListener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+}
+
+func (h *CallStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	event, err := ParseCallStatusEvent(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := event.CallSID + "/" + string(event.CallStatus) + "/" + strconv.Itoa(event.SequenceNumber)
+	h.deduper.serve(w, key, h.DedupeWindow, func(w http.ResponseWriter) {
+		h.mu.Lock()
+		listeners := append([]CallStatusListener{}, h.listeners...)
+		h.mu.Unlock()
+
+		for _, listener := range listeners {
+			err := listener(r.Context(), event)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,77 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCallStatusEvent(t *testing.T) {
+	event, err := ParseCallStatusEvent(url.Values{
+		"CallSid":        {"CA1"},
+		"CallStatus":     {"completed"},
+		"Direction":      {"outbound-api"},
+		"CallDuration":   {"42"},
+		"SequenceNumber": {"3"},
+		"CallbackSource": {"call-progress-events"},
+		"Timestamp":      {"Tue, 07 Jan 2020 12:00:00 +0000"},
+		"AnsweredBy":     {"human"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CallStatusCompleted, event.CallStatus)
+	assert.Equal(t, CallDirectionOutboundAPI, event.Direction)
+	assert.Equal(t, 42, event.CallDuration)
+	assert.Equal(t, 3, event.SequenceNumber)
+	assert.Equal(t, AnsweredByHuman, event.AnsweredBy)
+	assert.True(t, time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC).Equal(event.Timestamp))
+
+	_, err = ParseCallStatusEvent(url.Values{"CallSid": {"CA1"}, "CallStatus": {"ringing"}, "Timestamp": {"now"}})
+	assert.EqualError(t, err, "Invalid parameter: Timestamp")
+
+	_, err = ParseCallStatusEvent(url.Values{"CallSid": {"CA1"}})
+	assert.EqualError(t, err, "Missing parameter: CallStatus")
+}
+
+func TestCallStatusHandler(t *testing.T) {
+	handler := NewCallStatusHandler(NewRequestValidator("token"))
+
+	var statuses []CallStatus
+	fail := false
+	handler.AddListener(func(ctx context.Context, event *CallStatusEvent) error {
+		if fail {
+			return errors.New("test error")
+		}
+
+		statuses = append(statuses, event.CallStatus)
+		return nil
+	})
+
+	send := func(status string, sequence string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/call-status", url.Values{
+			"CallSid":        {"CA1"},
+			"CallStatus":     {status},
+			"SequenceNumber": {sequence},
+		}))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("ringing", "0"))
+	assert.Equal(t, http.StatusNoContent, send("ringing", "0"))
+
+	fail = true
+	assert.Equal(t, http.StatusInternalServerError, send("completed", "1"))
+
+	fail = false
+	assert.Equal(t, http.StatusNoContent, send("completed", "1"))
+	assert.Equal(t, []CallStatus{CallStatusRinging, CallStatusCompleted}, statuses)
+}
@@ -0,0 +1,318 @@
+package twilio
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Call is a call resource.
+type Call struct {
+	SID            string        `json:"sid"`
+	AccountSID     string        `json:"account_sid"`
+	ParentCallSID  string        `json:"parent_call_sid"`
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	Status         CallStatus    `json:"status"`
+	Direction      CallDirection `json:"direction"`
+	AnsweredBy     AnsweredBy    `json:"answered_by"`
+	CallerName     string        `json:"caller_name"`
+	ForwardedFrom  string        `json:"forwarded_from"`
+	QueueTime      string        `json:"queue_time"`
+	Price          string        `json:"price"`
+	PriceUnit      string        `json:"price_unit"`
+	PhoneNumberSID string        `json:"phone_number_sid"`
+
+	// Duration is the length of a completed call in seconds.
+	Duration string `json:"duration"`
+
+	// The dates are in RFC1123Z format.
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// CallProgressEvent is a call status that is reported to a status callback.
+type CallProgressEvent string
+
+// The call statuses that can be reported to a status callback.
+const (
+	CallProgressInitiated CallProgressEvent = "initiated"
+	CallProgressRinging   CallProgressEvent = "ringing"
+	CallProgressAnswered  CallProgressEvent = "answered"
+	CallProgressCompleted CallProgressEvent = "completed"
+)
+
+// ErrCallInstructions is returned when creating a call without exactly one
+// of a URL, TwiML or an application SID.
+var ErrCallInstructions = errors.New("Exactly one of URL, Twiml or ApplicationSID is required")
+
+// CallParams are the parameters for creating an outbound call. Empty values
+// are not sent.
+type CallParams struct {
+	From string
+	To   string
+
+	// Exactly one of URL, Twiml and ApplicationSID tells Twilio what to do
+	// when the call is answered.
+	URL            string
+	Method         string
+	Twiml          string
+	ApplicationSID string
+
+	FallbackURL    string
+	FallbackMethod string
+
+	StatusCallback       string
+	StatusCallbackMethod string
+	StatusCallbackEvents []CallProgressEvent
+
+	CallerID   string
+	SendDigits string
+
+	// Timeout is how long to let the call ring in seconds, and TimeLimit is
+	// the maximum length of the call in seconds.
+	Timeout   int
+	TimeLimit int
+
+	// MachineDetection enables answering machine detection. With AsyncAMD,
+	// the call is connected immediately and the result is sent to
+	// AsyncAMDStatusCallback. The timeout is in seconds and the thresholds
+	// are in milliseconds.
+	MachineDetection                   MachineDetection
+	MachineDetectionTimeout            int
+	MachineDetectionSpeechThreshold    int
+	MachineDetectionSpeechEndThreshold int
+	MachineDetectionSilenceTimeout     int
+	AsyncAMD                           *bool
+	AsyncAMDStatusCallback             string
+	AsyncAMDStatusCallbackMethod       string
+
+	Record                        *bool
+	RecordingChannels             string
+	RecordingTrack                string
+	RecordingStatusCallback       string
+	RecordingStatusCallbackMethod string
+	RecordingStatusCallbackEvents []string
+	Trim                          string
+}
+
+func (params *CallParams) values() (url.Values, error) {
+	if params == nil {
+		return nil, ErrCallInstructions
+	}
+
+	instructions := 0
+	for _, value := range []string{params.URL, params.Twiml, params.ApplicationSID} {
+		if value != "" {
+			instructions++
+		}
+	}
+
+	if instructions != 1 {
+		return nil, ErrCallInstructions
+	}
+
+	v := url.Values{}
+	setValue(v, "From", params.From)
+	setValue(v, "To", params.To)
+	setValue(v, "Url", params.URL)
+	setValue(v, "Method", params.Method)
+	setValue(v, "Twiml", params.Twiml)
+	setValue(v, "ApplicationSid", params.ApplicationSID)
+	setValue(v, "FallbackUrl", params.FallbackURL)
+	setValue(v, "FallbackMethod", params.FallbackMethod)
+	setValue(v, "StatusCallback", params.StatusCallback)
+	setValue(v, "StatusCallbackMethod", params.StatusCallbackMethod)
+	for _, event := range params.StatusCallbackEvents {
+		v.Add("StatusCallbackEvent", string(event))
+	}
+	setValue(v, "CallerId", params.CallerID)
+	setValue(v, "SendDigits", params.SendDigits)
+	setInt(v, "Timeout", params.Timeout)
+	setInt(v, "TimeLimit", params.TimeLimit)
+	setValue(v, "MachineDetection", string(params.MachineDetection))
+	setInt(v, "MachineDetectionTimeout", params.MachineDetectionTimeout)
+	setInt(v, "MachineDetectionSpeechThreshold", params.MachineDetectionSpeechThreshold)
+	setInt(v, "MachineDetectionSpeechEndThreshold", params.MachineDetectionSpeechEndThreshold)
+	setInt(v, "MachineDetectionSilenceTimeout", params.MachineDetectionSilenceTimeout)
+	setBool(v, "AsyncAmd", params.AsyncAMD)
+	setValue(v, "AsyncAmdStatusCallback", params.AsyncAMDStatusCallback)
+	setValue(v, "AsyncAmdStatusCallbackMethod", params.AsyncAMDStatusCallbackMethod)
+	setBool(v, "Record", params.Record)
+	setValue(v, "RecordingChannels", params.RecordingChannels)
+	setValue(v, "RecordingTrack", params.RecordingTrack)
+	setValue(v, "RecordingStatusCallback", params.RecordingStatusCallback)
+	setValue(v, "RecordingStatusCallbackMethod", params.RecordingStatusCallbackMethod)
+	setValues(v, "RecordingStatusCallbackEvent", params.RecordingStatusCallbackEvents)
+	setValue(v, "Trim", params.Trim)
+
+	return v, nil
+}
+
+// CallUpdateParams are the parameters for updating an in-progress call.
+// Empty values are not sent.
+type CallUpdateParams struct {
+	// URL or Twiml redirects the call to new instructions.
+	URL    string
+	Method string
+	Twiml  string
+
+	// Status ends the call. Use canceled for a queued or ringing call and
+	// completed for an in-progress call.
+	Status CallStatus
+
+	FallbackURL          string
+	FallbackMethod       string
+	StatusCallback       string
+	StatusCallbackMethod string
+	TimeLimit            int
+}
+
+func (params *CallUpdateParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "Url", params.URL)
+	setValue(v, "Method", params.Method)
+	setValue(v, "Twiml", params.Twiml)
+	setValue(v, "Status", string(params.Status))
+	setValue(v, "FallbackUrl", params.FallbackURL)
+	setValue(v, "FallbackMethod", params.FallbackMethod)
+	setValue(v, "StatusCallback", params.StatusCallback)
+	setValue(v, "StatusCallbackMethod", params.StatusCallbackMethod)
+	setInt(v, "TimeLimit", params.TimeLimit)
+	return v
+}
+
+// CallListParams filters the calls returned when listing calls. Empty values
+// are not sent. The times are compared by date only.
+type CallListParams struct {
+	From            string
+	To              string
+	ParentCallSID   string
+	Status          CallStatus
+	StartTimeAfter  time.Time
+	StartTimeBefore time.Time
+	EndTimeAfter    time.Time
+	EndTimeBefore   time.Time
+	PageSize        int
+}
+
+func (params *CallListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "From", params.From)
+	setValue(v, "To", params.To)
+	setValue(v, "ParentCallSid", params.ParentCallSID)
+	setValue(v, "Status", string(params.Status))
+	setDate(v, "StartTime>", params.StartTimeAfter)
+	setDate(v, "StartTime<", params.StartTimeBefore)
+	setDate(v, "EndTime>", params.EndTimeAfter)
+	setDate(v, "EndTime<", params.EndTimeBefore)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+type callPage struct {
+	Calls []*Call `json:"calls"`
+	apiPageMeta
+}
+
+func (p *callPage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// CallIterator iterates over a list of calls, most recent first, fetching
+// pages as needed.
+type CallIterator struct {
+	pager   pager
+	items   []*Call
+	current *Call
+}
+
+// Next advances to the next call. It returns false when there are no more
+// calls or an error occurred.
+func (it *CallIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p callPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Calls
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Call returns the current call.
+func (it *CallIterator) Call() *Call {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *CallIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) CreateCall(params *CallParams) (*Call, error) {
+	v, err := params.values()
+	if err != nil {
+		return nil, err
+	}
+
+	var call Call
+	err = client.create(client.accountURL("Calls"), v, &call)
+	if err != nil {
+		return nil, err
+	}
+
+	return &call, nil
+}
+
+func (client *clientImpl) FetchCall(sid string) (*Call, error) {
+	var call Call
+	err := client.fetch(client.accountURL("Calls", sid), &call)
+	if err != nil {
+		return nil, err
+	}
+
+	return &call, nil
+}
+
+func (client *clientImpl) ListCalls(params *CallListParams) *CallIterator {
+	requestURL := client.accountURL("Calls")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &CallIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) UpdateCall(sid string, params *CallUpdateParams) (*Call, error) {
+	var call Call
+	err := client.update(client.accountURL("Calls", sid), params.values(), &call)
+	if err != nil {
+		return nil, err
+	}
+
+	return &call, nil
+}
+
+func (client *clientImpl) HangUpCall(sid string) (*Call, error) {
+	return client.UpdateCall(sid, &CallUpdateParams{Status: CallStatusCompleted})
+}
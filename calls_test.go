@@ -0,0 +1,166 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCallsClientUsingMockServer(url string) Client {
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = url
+	return NewClient(opts)
+}
+
+func TestCreateCallUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{
+			"From":                    {"+14155552345"},
+			"To":                      {"+15108675310"},
+			"Twiml":                   {"<Response><Say>Hi</Say></Response>"},
+			"StatusCallback":          {"https://example.com/status"},
+			"StatusCallbackEvent":     {"initiated", "answered"},
+			"Timeout":                 {"20"},
+			"MachineDetection":        {"Enable"},
+			"MachineDetectionTimeout": {"15"},
+			"Record":                  {"true"},
+		}, r.PostForm)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid": "CA1", "status": "queued", "direction": "outbound-api"}`))
+	})
+
+	record := true
+	call, err := newCallsClientUsingMockServer(server.URL).CreateCall(&CallParams{
+		From:                    "+14155552345",
+		To:                      "+15108675310",
+		Twiml:                   "<Response><Say>Hi</Say></Response>",
+		StatusCallback:          "https://example.com/status",
+		StatusCallbackEvents:    []CallProgressEvent{CallProgressInitiated, CallProgressAnswered},
+		Timeout:                 20,
+		MachineDetection:        "Enable",
+		MachineDetectionTimeout: 15,
+		Record:                  &record,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Call{
+		SID:       "CA1",
+		Status:    CallStatusQueued,
+		Direction: CallDirectionOutboundAPI,
+	}, call)
+}
+
+func TestCreateCallRequiresInstructions(t *testing.T) {
+	client := newCallsClientUsingMockServer("http://127.0.0.1:0")
+
+	_, err := client.CreateCall(&CallParams{From: "+14155552345", To: "+15108675310"})
+	assert.Equal(t, ErrCallInstructions, err)
+
+	_, err = client.CreateCall(&CallParams{URL: "https://example.com/voice", Twiml: "<Response/>"})
+	assert.Equal(t, ErrCallInstructions, err)
+
+	_, err = client.CreateCall(nil)
+	assert.Equal(t, ErrCallInstructions, err)
+}
+
+func TestCallUpdateParamsNil(t *testing.T) {
+	var params *CallUpdateParams
+	assert.Empty(t, params.values())
+}
+
+func TestFetchCallUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls/CA1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "CA1", "status": "completed", "duration": "42"}`))
+	})
+
+	call, err := newCallsClientUsingMockServer(server.URL).FetchCall("CA1")
+	assert.NoError(t, err)
+	assert.Equal(t, CallStatusCompleted, call.Status)
+	assert.Equal(t, "42", call.Duration)
+
+	_, err = newCallsClientUsingMockServer(server.URL).FetchCall("CA2")
+	assert.Equal(t, &UnexpectedResponseError{Expected: http.StatusOK, StatusCode: http.StatusNotFound}, err)
+}
+
+func TestListCallsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "in-progress", r.URL.Query().Get("Status"))
+		assert.Equal(t, "2019-06-01", r.URL.Query().Get("StartTime>"))
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("Page") == "1" {
+			fmt.Fprint(w, `{"calls": [{"sid": "CA3"}], "next_page_uri": null}`)
+			return
+		}
+
+		fmt.Fprint(w, `{
+	"calls": [{"sid": "CA1"}, {"sid": "CA2"}],
+	"next_page_uri": "/Accounts/sid/Calls.json?Status=in-progress&StartTime%3E=2019-06-01&Page=1"
+}`)
+	})
+
+	it := newCallsClientUsingMockServer(server.URL).ListCalls(&CallListParams{
+		Status:         CallStatusInProgress,
+		StartTimeAfter: time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	var sids []string
+	for it.Next() {
+		sids = append(sids, it.Call().SID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"CA1", "CA2", "CA3"}, sids)
+}
+
+func TestUpdateCallUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	var forms []url.Values
+	mux.HandleFunc("/Accounts/sid/Calls/CA1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"sid": "CA1", "status": "%s"}`, r.PostForm.Get("Status"))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	_, err := client.UpdateCall("CA1", &CallUpdateParams{
+		URL:    "https://example.com/transfer",
+		Method: "POST",
+	})
+	assert.NoError(t, err)
+
+	call, err := client.HangUpCall("CA1")
+	assert.NoError(t, err)
+	assert.Equal(t, CallStatusCompleted, call.Status)
+
+	assert.Equal(t, []url.Values{
+		{"Url": {"https://example.com/transfer"}, "Method": {"POST"}},
+		{"Status": {"completed"}},
+	}, forms)
+}
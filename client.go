@@ -1,5 +1,10 @@
 package twilio
 
+import (
+	"io"
+	"time"
+)
+
 // CountryCodeNone can be used with the country code is optional.
 const CountryCodeNone = ""
 
@@ -17,4 +22,80 @@ type Client interface {
 		to string,
 		body string,
 	) (*SMSSendMessageResponse, error)
+
+	ScheduleSMSMessage(
+		messagingServiceSID string,
+		to string,
+		body string,
+		sendAt time.Time,
+	) (*SMSSendMessageResponse, error)
+
+	FetchSMSMessage(sid string) (*SMSSendMessageResponse, error)
+	ListSMSMessages(params *SMSListMessagesParams) *SMSMessageIterator
+
+	CreateCall(params *CallParams) (*Call, error)
+	FetchCall(sid string) (*Call, error)
+	ListCalls(params *CallListParams) *CallIterator
+	UpdateCall(sid string, params *CallUpdateParams) (*Call, error)
+	HangUpCall(sid string) (*Call, error)
+
+	FetchRecording(sid string) (*Recording, error)
+	ListRecordings(params *RecordingListParams) *RecordingIterator
+	ListCallRecordings(callSID string, params *RecordingListParams) *RecordingIterator
+	DeleteRecording(sid string) error
+	StartCallRecording(callSID string, params *CallRecordingParams) (*Recording, error)
+	UpdateCallRecording(
+		callSID string,
+		sid string,
+		status RecordingStatus,
+		pauseBehavior string,
+	) (*Recording, error)
+	DownloadRecording(sid string, format RecordingFormat) (io.ReadCloser, error)
+
+	FetchConference(sid string) (*Conference, error)
+	ListConferences(params *ConferenceListParams) *ConferenceIterator
+	UpdateConference(sid string, params *ConferenceUpdateParams) (*Conference, error)
+	EndConference(sid string) (*Conference, error)
+
+	AddConferenceParticipant(
+		conferenceSID string,
+		params *ConferenceParticipantParams,
+	) (*ConferenceParticipant, error)
+	FetchConferenceParticipant(conferenceSID string, callSID string) (*ConferenceParticipant, error)
+	ListConferenceParticipants(
+		conferenceSID string,
+		params *ConferenceParticipantListParams,
+	) *ConferenceParticipantIterator
+	UpdateConferenceParticipant(
+		conferenceSID string,
+		callSID string,
+		params *ConferenceParticipantUpdateParams,
+	) (*ConferenceParticipant, error)
+	KickConferenceParticipant(conferenceSID string, callSID string) error
+
+	CreateQueue(params *QueueParams) (*Queue, error)
+	FetchQueue(sid string) (*Queue, error)
+	ListQueues(params *QueueListParams) *QueueIterator
+	UpdateQueue(sid string, params *QueueParams) (*Queue, error)
+	DeleteQueue(sid string) error
+
+	FetchQueueMember(queueSID string, callSID string) (*QueueMember, error)
+	ListQueueMembers(queueSID string, params *QueueMemberListParams) *QueueMemberIterator
+	DequeueMember(
+		queueSID string,
+		callSID string,
+		twimlURL string,
+		method string,
+	) (*QueueMember, error)
+
+	FetchTranscription(sid string) (*Transcription, error)
+	ListTranscriptions(params *TranscriptionListParams) *TranscriptionIterator
+	ListRecordingTranscriptions(
+		recordingSID string,
+		params *TranscriptionListParams,
+	) *TranscriptionIterator
+	DeleteTranscription(sid string) error
+	DownloadTranscriptionText(sid string) (io.ReadCloser, error)
+
+	Conversations() Conversations
 }
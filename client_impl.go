@@ -3,8 +3,11 @@ package twilio
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // NewClient will create a new client with the given options.
@@ -18,6 +21,25 @@ type clientImpl struct {
 	opts *Options
 }
 
+// UnexpectedResponseError is returned when Twilio responds with a status code
+// other than the one expected.
+type UnexpectedResponseError struct {
+	Expected   int
+	StatusCode int
+}
+
+func (err *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf(
+		"Unexpected response. Expected %d but found %d",
+		err.Expected,
+		err.StatusCode)
+}
+
+// Temporary returns true when the request may succeed if it is retried.
+func (err *UnexpectedResponseError) Temporary() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
+}
+
 func (client *clientImpl) do(
 	req *http.Request,
 	authorize bool,
@@ -34,10 +56,11 @@ func (client *clientImpl) do(
 	}
 
 	if resp.StatusCode != expectedStatusCode {
-		return fmt.Errorf(
-			"Unexpected response. Expected %d but found %d",
-			expectedStatusCode,
-			resp.StatusCode)
+		resp.Body.Close()
+		return &UnexpectedResponseError{
+			Expected:   expectedStatusCode,
+			StatusCode: resp.StatusCode,
+		}
 	}
 
 	if responseObject != nil {
@@ -55,3 +78,102 @@ func (client *clientImpl) do(
 
 	return nil
 }
+
+// stream requests a media file and returns its body without reading it, so
+// that large files are not held in memory. The caller must close the body.
+func (client *clientImpl) stream(requestURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(client.opts.SID, client.opts.Token)
+
+	resp, err := client.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &UnexpectedResponseError{
+			Expected:   http.StatusOK,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	return &streamBody{
+		Reader: client.opts.ReaderFunc(resp.Body),
+		Closer: resp.Body,
+	}, nil
+}
+
+type streamBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (client *clientImpl) fetch(
+	requestURL string,
+	responseObject interface{},
+) error {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	return client.do(req, true, http.StatusOK, responseObject)
+}
+
+func (client *clientImpl) create(
+	requestURL string,
+	v url.Values,
+	responseObject interface{},
+) error {
+	req, err := newFormRequest(http.MethodPost, requestURL, v)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, true, http.StatusCreated, responseObject)
+}
+
+func (client *clientImpl) update(
+	requestURL string,
+	v url.Values,
+	responseObject interface{},
+) error {
+	req, err := newFormRequest(http.MethodPost, requestURL, v)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, true, http.StatusOK, responseObject)
+}
+
+func (client *clientImpl) remove(requestURL string) error {
+	req, err := http.NewRequest(http.MethodDelete, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, true, http.StatusNoContent, nil)
+}
+
+func newFormRequest(
+	method string,
+	requestURL string,
+	v url.Values,
+) (*http.Request, error) {
+	req, err := http.NewRequest(method, requestURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
@@ -7,12 +7,18 @@ import (
 
 // Options are the configuration options for the client.
 type Options struct {
-	LookupBaseURL string
-	APIBaseURL    string
-	HTTPClient    *http.Client
-	ReaderFunc    func(io.Reader) io.Reader
-	SID           string
-	Token         string
+	LookupBaseURL        string
+	APIBaseURL           string
+	ConversationsBaseURL string
+	HTTPClient           *http.Client
+	ReaderFunc           func(io.Reader) io.Reader
+	SID                  string
+	Token                string
+
+	// AuthToken is the account's auth token, which Twilio uses to sign
+	// webhooks. It is only needed when SID and Token are an API key and
+	// secret.
+	AuthToken string
 }
 
 // NewOptions will create new options with default values.
@@ -22,11 +28,12 @@ func NewOptions(sid, token string) *Options {
 	}
 
 	return &Options{
-		LookupBaseURL: "https://lookups.twilio.com",
-		APIBaseURL:    "https://api.twilio.com/2010-04-01",
-		HTTPClient:    &http.Client{},
-		ReaderFunc:    readerFunc,
-		SID:           sid,
-		Token:         token,
+		LookupBaseURL:        "https://lookups.twilio.com",
+		APIBaseURL:           "https://api.twilio.com/2010-04-01",
+		ConversationsBaseURL: "https://conversations.twilio.com",
+		HTTPClient:           &http.Client{},
+		ReaderFunc:           readerFunc,
+		SID:                  sid,
+		Token:                token,
 	}
 }
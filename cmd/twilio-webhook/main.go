@@ -0,0 +1,192 @@
+// Command twilio-webhook sends signed Twilio webhooks to a local server, so
+// that webhook handlers can be tested without Twilio.
+//
+// Usage:
+//
+//	twilio-webhook [flags] sms|status|voice|call-status
+//
+// The webhook's parameters are generated from flags, then replaced by the
+// parameters in the JSON fixture and by -param flags. The request is signed
+// with the auth token exactly like Twilio signs it, and the response is
+// printed.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	twilio "github.com/jeremybower/go-twilio"
+)
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, http.DefaultClient))
+}
+
+func run(args []string, stdout, stderr io.Writer, client *http.Client) int {
+	flags := flag.NewFlagSet("twilio-webhook", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: twilio-webhook [flags] sms|status|voice|call-status")
+		flags.PrintDefaults()
+	}
+
+	p := &webhookParams{}
+	var media, params stringsFlag
+	requestURL := flags.String("url", "", "URL of the webhook handler (required)")
+	token := flags.String("token", "", "auth token used to sign the request (default $TWILIO_AUTH_TOKEN)")
+	fixture := flags.String("fixture", "", "JSON file with an object of parameters")
+	verbose := flags.Bool("v", false, "print the request's parameters and signature")
+	flags.StringVar(&p.AccountSID, "account", "AC00000000000000000000000000000000", "account SID")
+	flags.StringVar(&p.SID, "sid", "", "message or call SID (default random)")
+	flags.StringVar(&p.From, "from", "+15005550006", "sender's phone number")
+	flags.StringVar(&p.To, "to", "+15005550001", "recipient's phone number")
+	flags.StringVar(&p.Body, "body", "", "body of an incoming message")
+	flags.Var(&media, "media", "media URL of an incoming message (repeatable)")
+	flags.StringVar(&p.Status, "status", "", "message or call status")
+	flags.StringVar(&p.ErrorCode, "error-code", "", "error code of a message status")
+	flags.StringVar(&p.Direction, "direction", "", "call direction (default inbound)")
+	flags.StringVar(&p.Digits, "digits", "", "digits gathered during a call")
+	flags.StringVar(&p.Speech, "speech", "", "speech gathered during a call")
+	flags.StringVar(&p.Confidence, "confidence", "", "confidence of the speech result")
+	flags.Var(&params, "param", "extra parameter as name=value (repeatable)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 || *requestURL == "" {
+		flags.Usage()
+		return 2
+	}
+
+	// The token is read from the environment after parsing so that it is not
+	// printed as the flag's default.
+	if *token == "" {
+		*token = os.Getenv("TWILIO_AUTH_TOKEN")
+	}
+
+	if *token == "" {
+		fmt.Fprintln(stderr, "An auth token is required to sign the request")
+		return 2
+	}
+
+	p.Media = media
+	form, err := buildWebhook(flags.Arg(0), p)
+	if err == nil && *fixture != "" {
+		err = applyFixture(form, *fixture)
+	}
+
+	if err == nil {
+		err = applyParams(form, params)
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	signature := twilio.ComputeSignature(*token, *requestURL, form)
+	if *verbose {
+		fmt.Fprintf(stderr, "POST %s\n%s: %s\n%s\n\n", *requestURL, twilio.SignatureHeader, signature, form.Encode())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, *requestURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(twilio.SignatureHeader, signature)
+	req.Header.Set("User-Agent", "TwilioProxy/1.1")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintln(stderr, resp.Status)
+	stdout.Write(body)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		fmt.Fprintln(stdout)
+	}
+
+	if resp.StatusCode >= 400 {
+		return 1
+	}
+
+	return 0
+}
+
+// applyFixture replaces parameters with the ones in a JSON file. The file
+// contains an object whose values are strings or arrays of strings.
+func applyFixture(form url.Values, path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fixture map[string]interface{}
+	err = json.Unmarshal(b, &fixture)
+	if err != nil {
+		return fmt.Errorf("Invalid fixture %s: %v", path, err)
+	}
+
+	for key, value := range fixture {
+		switch value := value.(type) {
+		case string:
+			form.Set(key, value)
+		case []interface{}:
+			form.Del(key)
+			for _, v := range value {
+				form.Add(key, fmt.Sprint(v))
+			}
+		case nil:
+			form.Del(key)
+		default:
+			form.Set(key, fmt.Sprint(value))
+		}
+	}
+
+	return nil
+}
+
+func applyParams(form url.Values, params []string) error {
+	for _, param := range params {
+		i := strings.Index(param, "=")
+		if i <= 0 {
+			return fmt.Errorf("Invalid parameter: %s", param)
+		}
+
+		form.Set(param[:i], param[i+1:])
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// +build unit
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	twilio "github.com/jeremybower/go-twilio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSignsIncomingMessage(t *testing.T) {
+	var form map[string][]string
+	server := httptest.NewServer(twilio.NewRequestValidator("token").Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			form = r.PostForm
+			w.Header().Set("Content-Type", "text/xml")
+			w.Write([]byte("<Response><Message>Hi</Message></Response>"))
+		})))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "twilio-webhook")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fixture := filepath.Join(dir, "fixture.json")
+	err = ioutil.WriteFile(fixture, []byte(`{"FromCity": "OAKLAND", "NumSegments": 2}`), 0600)
+	assert.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{
+		"-url", server.URL + "/sms",
+		"-token", "token",
+		"-sid", "SM1",
+		"-body", "HELP",
+		"-media", "https://example.com/cat.jpg",
+		"-fixture", fixture,
+		"-param", "ToCountry=US",
+		"sms",
+	}, &stdout, &stderr, server.Client())
+
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "<Response><Message>Hi</Message></Response>\n", stdout.String())
+	assert.Equal(t, "SM1", form["MessageSid"][0])
+	assert.Equal(t, "HELP", form["Body"][0])
+	assert.Equal(t, "1", form["NumMedia"][0])
+	assert.Equal(t, "image/jpeg", form["MediaContentType0"][0])
+	assert.Equal(t, "OAKLAND", form["FromCity"][0])
+	assert.Equal(t, "2", form["NumSegments"][0])
+	assert.Equal(t, "US", form["ToCountry"][0])
+}
+
+func TestRunReportsRejectedRequest(t *testing.T) {
+	server := httptest.NewServer(twilio.NewRequestValidator("other").Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-url", server.URL, "-token", "token", "status"}, &stdout, &stderr, server.Client())
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "403 Forbidden")
+}
+
+func TestRunReadsTokenFromEnvironment(t *testing.T) {
+	server := httptest.NewServer(twilio.NewRequestValidator("secret").Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	defer server.Close()
+
+	os.Setenv("TWILIO_AUTH_TOKEN", "secret")
+	defer os.Unsetenv("TWILIO_AUTH_TOKEN")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-h"}, &stdout, &stderr, server.Client())
+	assert.Equal(t, 2, code)
+	assert.NotContains(t, stderr.String(), "secret")
+
+	stderr.Reset()
+	code = run([]string{"-url", server.URL + "/status", "status"}, &stdout, &stderr, server.Client())
+	assert.Equal(t, 0, code, stderr.String())
+}
+
+func TestBuildWebhook(t *testing.T) {
+	form, err := buildWebhook("voice", &webhookParams{SID: "CA1", Digits: "1", Speech: "sales"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CA1", form.Get("CallSid"))
+	assert.Equal(t, "ringing", form.Get("CallStatus"))
+	assert.Equal(t, "inbound", form.Get("Direction"))
+	assert.Equal(t, "1", form.Get("Digits"))
+	assert.Equal(t, "0.9", form.Get("Confidence"))
+
+	form, err = buildWebhook("call-status", &webhookParams{})
+	assert.NoError(t, err)
+	assert.Len(t, form.Get("CallSid"), 34)
+	assert.Equal(t, "completed", form.Get("CallStatus"))
+
+	_, err = buildWebhook("fax", &webhookParams{})
+	assert.EqualError(t, err, "Unknown webhook: fax")
+
+	assert.Equal(t, 1, segments("hello"))
+	assert.Equal(t, 2, segments(string(make([]byte, 161))))
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// webhookParams are the flags used to build a webhook's parameters.
+type webhookParams struct {
+	AccountSID string
+	SID        string
+	From       string
+	To         string
+	Body       string
+	Media      []string
+	Status     string
+	ErrorCode  string
+	Direction  string
+	Digits     string
+	Speech     string
+	Confidence string
+}
+
+// buildWebhook returns the parameters Twilio sends for a kind of webhook.
+func buildWebhook(kind string, p *webhookParams) (url.Values, error) {
+	switch kind {
+	case "sms":
+		return buildIncomingMessage(p), nil
+	case "status":
+		return buildMessageStatus(p), nil
+	case "voice":
+		return buildVoice(p), nil
+	case "call-status":
+		return buildCallStatus(p), nil
+	default:
+		return nil, fmt.Errorf("Unknown webhook: %s", kind)
+	}
+}
+
+func buildIncomingMessage(p *webhookParams) url.Values {
+	sid := orDefault(p.SID, newSID("SM"))
+	form := url.Values{
+		"MessageSid":    {sid},
+		"SmsMessageSid": {sid},
+		"SmsSid":        {sid},
+		"AccountSid":    {p.AccountSID},
+		"From":          {p.From},
+		"To":            {p.To},
+		"Body":          {p.Body},
+		"NumMedia":      {strconv.Itoa(len(p.Media))},
+		"NumSegments":   {strconv.Itoa(segments(p.Body))},
+		"SmsStatus":     {"received"},
+		"ApiVersion":    {"2010-04-01"},
+	}
+
+	for i, media := range p.Media {
+		n := strconv.Itoa(i)
+		form.Set("MediaUrl"+n, media)
+		form.Set("MediaContentType"+n, contentType(media))
+	}
+
+	return form
+}
+
+func buildMessageStatus(p *webhookParams) url.Values {
+	sid := orDefault(p.SID, newSID("SM"))
+	status := orDefault(p.Status, "delivered")
+	form := url.Values{
+		"MessageSid":    {sid},
+		"SmsSid":        {sid},
+		"AccountSid":    {p.AccountSID},
+		"From":          {p.From},
+		"To":            {p.To},
+		"MessageStatus": {status},
+		"SmsStatus":     {status},
+		"ApiVersion":    {"2010-04-01"},
+	}
+
+	if p.ErrorCode != "" {
+		form.Set("ErrorCode", p.ErrorCode)
+	}
+
+	return form
+}
+
+func buildVoice(p *webhookParams) url.Values {
+	form := buildCall(p, orDefault(p.Status, "ringing"))
+	if p.Digits != "" {
+		form.Set("Digits", p.Digits)
+	}
+
+	if p.Speech != "" {
+		form.Set("SpeechResult", p.Speech)
+		form.Set("Confidence", orDefault(p.Confidence, "0.9"))
+	}
+
+	return form
+}
+
+func buildCallStatus(p *webhookParams) url.Values {
+	form := buildCall(p, orDefault(p.Status, "completed"))
+	form.Set("CallbackSource", "call-progress-events")
+	form.Set("SequenceNumber", "0")
+	return form
+}
+
+func buildCall(p *webhookParams, status string) url.Values {
+	return url.Values{
+		"CallSid":    {orDefault(p.SID, newSID("CA"))},
+		"AccountSid": {p.AccountSID},
+		"From":       {p.From},
+		"To":         {p.To},
+		"Caller":     {p.From},
+		"Called":     {p.To},
+		"CallStatus": {status},
+		"Direction":  {orDefault(p.Direction, "inbound")},
+		"ApiVersion": {"2010-04-01"},
+	}
+}
+
+// segments returns the number of SMS segments needed for the body, assuming
+// the GSM-7 encoding unless the body has other characters.
+func segments(body string) int {
+	single, multi := 160, 153
+	for _, r := range body {
+		if r > 0x7f {
+			single, multi = 70, 67
+			break
+		}
+	}
+
+	n := len([]rune(body))
+	if n <= single {
+		return 1
+	}
+
+	return (n + multi - 1) / multi
+}
+
+func contentType(mediaURL string) string {
+	lower := strings.ToLower(mediaURL)
+	for ext, t := range map[string]string{
+		".jpg":  "image/jpeg",
+		".jpeg": "image/jpeg",
+		".png":  "image/png",
+		".gif":  "image/gif",
+		".mp4":  "video/mp4",
+		".pdf":  "application/pdf",
+	} {
+		if strings.HasSuffix(lower, ext) {
+			return t
+		}
+	}
+
+	return "application/octet-stream"
+}
+
+func newSID(prefix string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
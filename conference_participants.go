@@ -0,0 +1,285 @@
+package twilio
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ConferenceParticipantStatus is the status of a conference participant.
+type ConferenceParticipantStatus string
+
+// The statuses a conference participant can have.
+const (
+	ConferenceParticipantQueued     ConferenceParticipantStatus = "queued"
+	ConferenceParticipantConnecting ConferenceParticipantStatus = "connecting"
+	ConferenceParticipantRinging    ConferenceParticipantStatus = "ringing"
+	ConferenceParticipantConnected  ConferenceParticipantStatus = "connected"
+	ConferenceParticipantComplete   ConferenceParticipantStatus = "complete"
+	ConferenceParticipantFailed     ConferenceParticipantStatus = "failed"
+)
+
+// ConferenceParticipant is a call connected to a conference.
+type ConferenceParticipant struct {
+	CallSID                string                      `json:"call_sid"`
+	ConferenceSID          string                      `json:"conference_sid"`
+	AccountSID             string                      `json:"account_sid"`
+	Label                  string                      `json:"label"`
+	Status                 ConferenceParticipantStatus `json:"status"`
+	Muted                  bool                        `json:"muted"`
+	Hold                   bool                        `json:"hold"`
+	Coaching               bool                        `json:"coaching"`
+	CallSIDToCoach         string                      `json:"call_sid_to_coach"`
+	StartConferenceOnEnter bool                        `json:"start_conference_on_enter"`
+	EndConferenceOnExit    bool                        `json:"end_conference_on_exit"`
+
+	// The dates are in RFC1123Z format.
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// ConferenceParticipantParams are the parameters for calling a phone number
+// and adding it to a conference. Empty values are not sent.
+type ConferenceParticipantParams struct {
+	From  string
+	To    string
+	Label string
+
+	StatusCallback       string
+	StatusCallbackMethod string
+	StatusCallbackEvents []CallProgressEvent
+
+	// Timeout is how long to let the call ring in seconds, and TimeLimit is
+	// the maximum length of the call in seconds.
+	Timeout   int
+	TimeLimit int
+
+	Muted                  *bool
+	Beep                   string
+	StartConferenceOnEnter *bool
+	EndConferenceOnExit    *bool
+	EarlyMedia             *bool
+	WaitURL                string
+	WaitMethod             string
+	MaxParticipants        int
+
+	// Coaching makes the participant heard only by the participant with the
+	// call SID to coach.
+	Coaching       *bool
+	CallSIDToCoach string
+
+	Record                         *bool
+	ConferenceRecord               string
+	ConferenceStatusCallback       string
+	ConferenceStatusCallbackEvents []string
+
+	MachineDetection        MachineDetection
+	MachineDetectionTimeout int
+}
+
+func (params *ConferenceParticipantParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "From", params.From)
+	setValue(v, "To", params.To)
+	setValue(v, "Label", params.Label)
+	setValue(v, "StatusCallback", params.StatusCallback)
+	setValue(v, "StatusCallbackMethod", params.StatusCallbackMethod)
+	for _, event := range params.StatusCallbackEvents {
+		v.Add("StatusCallbackEvent", string(event))
+	}
+	setInt(v, "Timeout", params.Timeout)
+	setInt(v, "TimeLimit", params.TimeLimit)
+	setBool(v, "Muted", params.Muted)
+	setValue(v, "Beep", params.Beep)
+	setBool(v, "StartConferenceOnEnter", params.StartConferenceOnEnter)
+	setBool(v, "EndConferenceOnExit", params.EndConferenceOnExit)
+	setBool(v, "EarlyMedia", params.EarlyMedia)
+	setValue(v, "WaitUrl", params.WaitURL)
+	setValue(v, "WaitMethod", params.WaitMethod)
+	setInt(v, "MaxParticipants", params.MaxParticipants)
+	setBool(v, "Coaching", params.Coaching)
+	setValue(v, "CallSidToCoach", params.CallSIDToCoach)
+	setBool(v, "Record", params.Record)
+	setValue(v, "ConferenceRecord", params.ConferenceRecord)
+	setValue(v, "ConferenceStatusCallback", params.ConferenceStatusCallback)
+	setValues(v, "ConferenceStatusCallbackEvent", params.ConferenceStatusCallbackEvents)
+	setValue(v, "MachineDetection", string(params.MachineDetection))
+	setInt(v, "MachineDetectionTimeout", params.MachineDetectionTimeout)
+	return v
+}
+
+// ConferenceParticipantUpdateParams are the parameters for updating a
+// conference participant. Empty values are not sent.
+type ConferenceParticipantUpdateParams struct {
+	Muted *bool
+
+	// Hold puts the participant on hold, playing the TwiML at HoldURL, such
+	// as hold music, while they wait.
+	Hold       *bool
+	HoldURL    string
+	HoldMethod string
+
+	// AnnounceURL plays TwiML to the participant only.
+	AnnounceURL    string
+	AnnounceMethod string
+
+	Coaching       *bool
+	CallSIDToCoach string
+
+	EndConferenceOnExit *bool
+	BeepOnExit          *bool
+}
+
+func (params *ConferenceParticipantUpdateParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setBool(v, "Muted", params.Muted)
+	setBool(v, "Hold", params.Hold)
+	setValue(v, "HoldUrl", params.HoldURL)
+	setValue(v, "HoldMethod", params.HoldMethod)
+	setValue(v, "AnnounceUrl", params.AnnounceURL)
+	setValue(v, "AnnounceMethod", params.AnnounceMethod)
+	setBool(v, "Coaching", params.Coaching)
+	setValue(v, "CallSidToCoach", params.CallSIDToCoach)
+	setBool(v, "EndConferenceOnExit", params.EndConferenceOnExit)
+	setBool(v, "BeepOnExit", params.BeepOnExit)
+	return v
+}
+
+// ConferenceParticipantListParams filters the participants returned when
+// listing participants. Empty values are not sent.
+type ConferenceParticipantListParams struct {
+	Muted    *bool
+	Hold     *bool
+	Coaching *bool
+	PageSize int
+}
+
+func (params *ConferenceParticipantListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setBool(v, "Muted", params.Muted)
+	setBool(v, "Hold", params.Hold)
+	setBool(v, "Coaching", params.Coaching)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+type conferenceParticipantPage struct {
+	Participants []*ConferenceParticipant `json:"participants"`
+	apiPageMeta
+}
+
+func (p *conferenceParticipantPage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// ConferenceParticipantIterator iterates over a list of participants,
+// fetching pages as needed.
+type ConferenceParticipantIterator struct {
+	pager   pager
+	items   []*ConferenceParticipant
+	current *ConferenceParticipant
+}
+
+// Next advances to the next participant. It returns false when there are no
+// more participants or an error occurred.
+func (it *ConferenceParticipantIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conferenceParticipantPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Participants
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Participant returns the current participant.
+func (it *ConferenceParticipantIterator) Participant() *ConferenceParticipant {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConferenceParticipantIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) AddConferenceParticipant(
+	conferenceSID string,
+	params *ConferenceParticipantParams,
+) (*ConferenceParticipant, error) {
+	var participant ConferenceParticipant
+	err := client.create(
+		client.accountURL("Conferences", conferenceSID, "Participants"),
+		params.values(),
+		&participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (client *clientImpl) FetchConferenceParticipant(
+	conferenceSID string,
+	callSID string,
+) (*ConferenceParticipant, error) {
+	var participant ConferenceParticipant
+	err := client.fetch(client.accountURL("Conferences", conferenceSID, "Participants", callSID), &participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (client *clientImpl) ListConferenceParticipants(
+	conferenceSID string,
+	params *ConferenceParticipantListParams,
+) *ConferenceParticipantIterator {
+	requestURL := client.accountURL("Conferences", conferenceSID, "Participants")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &ConferenceParticipantIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) UpdateConferenceParticipant(
+	conferenceSID string,
+	callSID string,
+	params *ConferenceParticipantUpdateParams,
+) (*ConferenceParticipant, error) {
+	var participant ConferenceParticipant
+	err := client.update(
+		client.accountURL("Conferences", conferenceSID, "Participants", callSID),
+		params.values(),
+		&participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (client *clientImpl) KickConferenceParticipant(conferenceSID string, callSID string) error {
+	return client.remove(client.accountURL("Conferences", conferenceSID, "Participants", callSID))
+}
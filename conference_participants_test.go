@@ -0,0 +1,125 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddConferenceParticipantUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1/Participants.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{
+			"From":                {"+14155552345"},
+			"To":                  {"+15108675310"},
+			"Label":               {"supervisor"},
+			"Coaching":            {"true"},
+			"CallSidToCoach":      {"CA1"},
+			"EndConferenceOnExit": {"false"},
+			"StatusCallbackEvent": {"answered"},
+		}, r.PostForm)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call_sid": "CA2", "conference_sid": "CF1", "label": "supervisor", "coaching": true, "status": "queued"}`))
+	})
+
+	coaching, endOnExit := true, false
+	participant, err := newCallsClientUsingMockServer(server.URL).AddConferenceParticipant("CF1", &ConferenceParticipantParams{
+		From:                 "+14155552345",
+		To:                   "+15108675310",
+		Label:                "supervisor",
+		Coaching:             &coaching,
+		CallSIDToCoach:       "CA1",
+		EndConferenceOnExit:  &endOnExit,
+		StatusCallbackEvents: []CallProgressEvent{CallProgressAnswered},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ConferenceParticipant{
+		CallSID:       "CA2",
+		ConferenceSID: "CF1",
+		Label:         "supervisor",
+		Coaching:      true,
+		Status:        ConferenceParticipantQueued,
+	}, participant)
+}
+
+func TestConferenceParticipantOperationsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	var forms []url.Values
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1/Participants/CA1.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"call_sid": "CA1", "muted": false}`))
+
+		case http.MethodPost:
+			assert.NoError(t, r.ParseForm())
+			forms = append(forms, r.PostForm)
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"call_sid": "CA1", "muted": %t, "hold": %t}`,
+				r.PostForm.Get("Muted") == "true",
+				r.PostForm.Get("Hold") == "true")
+
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1/Participants.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("Muted"))
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"participants": [{"call_sid": "CA1", "muted": true}], "next_page_uri": null}`)
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	participant, err := client.FetchConferenceParticipant("CF1", "CA1")
+	assert.NoError(t, err)
+	assert.Equal(t, "CA1", participant.CallSID)
+
+	muted, hold := true, true
+	participant, err = client.UpdateConferenceParticipant("CF1", "CA1", &ConferenceParticipantUpdateParams{Muted: &muted})
+	assert.NoError(t, err)
+	assert.True(t, participant.Muted)
+
+	participant, err = client.UpdateConferenceParticipant("CF1", "CA1", &ConferenceParticipantUpdateParams{
+		Hold:    &hold,
+		HoldURL: "https://example.com/hold-music",
+	})
+	assert.NoError(t, err)
+	assert.True(t, participant.Hold)
+
+	assert.Equal(t, []url.Values{
+		{"Muted": {"true"}},
+		{"Hold": {"true"}, "HoldUrl": {"https://example.com/hold-music"}},
+	}, forms)
+
+	it := client.ListConferenceParticipants("CF1", &ConferenceParticipantListParams{Muted: &muted})
+	assert.True(t, it.Next())
+	assert.Equal(t, "CA1", it.Participant().CallSID)
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+
+	assert.NoError(t, client.KickConferenceParticipant("CF1", "CA1"))
+}
+
+func TestConferenceParticipantParamsNil(t *testing.T) {
+	var params *ConferenceParticipantParams
+	assert.Empty(t, params.values())
+
+	var updateParams *ConferenceParticipantUpdateParams
+	assert.Empty(t, updateParams.values())
+}
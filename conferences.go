@@ -0,0 +1,164 @@
+package twilio
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ConferenceStatus is the status of a conference.
+type ConferenceStatus string
+
+// The statuses a conference can have.
+const (
+	ConferenceStatusInit       ConferenceStatus = "init"
+	ConferenceStatusInProgress ConferenceStatus = "in-progress"
+	ConferenceStatusCompleted  ConferenceStatus = "completed"
+)
+
+// Conference is a conference resource.
+type Conference struct {
+	SID                     string           `json:"sid"`
+	AccountSID              string           `json:"account_sid"`
+	FriendlyName            string           `json:"friendly_name"`
+	Status                  ConferenceStatus `json:"status"`
+	Region                  string           `json:"region"`
+	ReasonConferenceEnded   string           `json:"reason_conference_ended"`
+	CallSIDEndingConference string           `json:"call_sid_ending_conference"`
+
+	// The dates are in RFC1123Z format.
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// ConferenceListParams filters the conferences returned when listing
+// conferences. Empty values are not sent. The times are compared by date
+// only.
+type ConferenceListParams struct {
+	FriendlyName      string
+	Status            ConferenceStatus
+	DateCreatedAfter  time.Time
+	DateCreatedBefore time.Time
+	PageSize          int
+}
+
+func (params *ConferenceListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "FriendlyName", params.FriendlyName)
+	setValue(v, "Status", string(params.Status))
+	setDate(v, "DateCreated>", params.DateCreatedAfter)
+	setDate(v, "DateCreated<", params.DateCreatedBefore)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+// ConferenceUpdateParams are the parameters for updating a conference. Empty
+// values are not sent.
+type ConferenceUpdateParams struct {
+	// Status ends the conference when it is completed.
+	Status ConferenceStatus
+
+	// AnnounceURL plays TwiML, such as Say or Play, to every participant.
+	AnnounceURL    string
+	AnnounceMethod string
+}
+
+func (params *ConferenceUpdateParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "Status", string(params.Status))
+	setValue(v, "AnnounceUrl", params.AnnounceURL)
+	setValue(v, "AnnounceMethod", params.AnnounceMethod)
+	return v
+}
+
+type conferencePage struct {
+	Conferences []*Conference `json:"conferences"`
+	apiPageMeta
+}
+
+func (p *conferencePage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// ConferenceIterator iterates over a list of conferences, most recent first,
+// fetching pages as needed.
+type ConferenceIterator struct {
+	pager   pager
+	items   []*Conference
+	current *Conference
+}
+
+// Next advances to the next conference. It returns false when there are no
+// more conferences or an error occurred.
+func (it *ConferenceIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conferencePage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Conferences
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Conference returns the current conference.
+func (it *ConferenceIterator) Conference() *Conference {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConferenceIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) FetchConference(sid string) (*Conference, error) {
+	var conference Conference
+	err := client.fetch(client.accountURL("Conferences", sid), &conference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conference, nil
+}
+
+func (client *clientImpl) ListConferences(params *ConferenceListParams) *ConferenceIterator {
+	requestURL := client.accountURL("Conferences")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &ConferenceIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) UpdateConference(
+	sid string,
+	params *ConferenceUpdateParams,
+) (*Conference, error) {
+	var conference Conference
+	err := client.update(client.accountURL("Conferences", sid), params.values(), &conference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conference, nil
+}
+
+func (client *clientImpl) EndConference(sid string) (*Conference, error) {
+	return client.UpdateConference(sid, &ConferenceUpdateParams{Status: ConferenceStatusCompleted})
+}
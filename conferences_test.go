@@ -0,0 +1,112 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchConferenceUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "CF1", "friendly_name": "Room 1234", "status": "in-progress"}`))
+	})
+
+	conference, err := newCallsClientUsingMockServer(server.URL).FetchConference("CF1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Conference{
+		SID:          "CF1",
+		FriendlyName: "Room 1234",
+		Status:       ConferenceStatusInProgress,
+	}, conference)
+}
+
+func TestListConferencesUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Conferences.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "in-progress", r.URL.Query().Get("Status"))
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("Page") == "1" {
+			fmt.Fprint(w, `{"conferences": [{"sid": "CF2"}], "next_page_uri": null}`)
+			return
+		}
+
+		fmt.Fprint(w, `{
+	"conferences": [{"sid": "CF1"}],
+	"next_page_uri": "/Accounts/sid/Conferences.json?Status=in-progress&Page=1"
+}`)
+	})
+
+	it := newCallsClientUsingMockServer(server.URL).ListConferences(&ConferenceListParams{
+		Status: ConferenceStatusInProgress,
+	})
+
+	var sids []string
+	for it.Next() {
+		sids = append(sids, it.Conference().SID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"CF1", "CF2"}, sids)
+}
+
+func TestUpdateConferenceUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	var forms []url.Values
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "CF1", "status": "completed"}`))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	_, err := client.UpdateConference("CF1", &ConferenceUpdateParams{
+		AnnounceURL: "https://example.com/announce",
+	})
+	assert.NoError(t, err)
+
+	conference, err := client.EndConference("CF1")
+	assert.NoError(t, err)
+	assert.Equal(t, ConferenceStatusCompleted, conference.Status)
+
+	assert.Equal(t, []url.Values{
+		{"AnnounceUrl": {"https://example.com/announce"}},
+		{"Status": {"completed"}},
+	}, forms)
+}
+
+func TestUpdateConferenceWithoutParamsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Conferences/CF1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Empty(t, r.PostForm)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "CF1", "status": "in-progress"}`))
+	})
+
+	conference, err := newCallsClientUsingMockServer(server.URL).UpdateConference("CF1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "CF1", conference.SID)
+}
@@ -0,0 +1,224 @@
+package twilio
+
+import (
+	"net/url"
+	"time"
+)
+
+// Conversations is a group of APIs related to Twilio Conversations.
+type Conversations interface {
+	CreateConversation(params *ConversationParams) (*Conversation, error)
+	FetchConversation(sid string) (*Conversation, error)
+	ListConversations() *ConversationIterator
+	UpdateConversation(sid string, params *ConversationParams) (*Conversation, error)
+	DeleteConversation(sid string) error
+
+	CreateParticipant(
+		conversationSID string,
+		params *ConversationParticipantParams,
+	) (*ConversationParticipant, error)
+	FetchParticipant(conversationSID, sid string) (*ConversationParticipant, error)
+	ListParticipants(conversationSID string) *ConversationParticipantIterator
+	UpdateParticipant(
+		conversationSID string,
+		sid string,
+		params *ConversationParticipantParams,
+	) (*ConversationParticipant, error)
+	DeleteParticipant(conversationSID, sid string) error
+
+	CreateMessage(
+		conversationSID string,
+		params *ConversationMessageParams,
+	) (*ConversationMessage, error)
+	FetchMessage(conversationSID, sid string) (*ConversationMessage, error)
+	ListMessages(conversationSID string) *ConversationMessageIterator
+	UpdateMessage(
+		conversationSID string,
+		sid string,
+		params *ConversationMessageParams,
+	) (*ConversationMessage, error)
+	DeleteMessage(conversationSID, sid string) error
+
+	CreateWebhook(
+		conversationSID string,
+		params *ConversationWebhookParams,
+	) (*ConversationWebhook, error)
+	FetchWebhook(conversationSID, sid string) (*ConversationWebhook, error)
+	ListWebhooks(conversationSID string) *ConversationWebhookIterator
+	UpdateWebhook(
+		conversationSID string,
+		sid string,
+		params *ConversationWebhookParams,
+	) (*ConversationWebhook, error)
+	DeleteWebhook(conversationSID, sid string) error
+
+	CreateRole(params *ConversationRoleParams) (*ConversationRole, error)
+	FetchRole(sid string) (*ConversationRole, error)
+	ListRoles() *ConversationRoleIterator
+	UpdateRole(sid string, params *ConversationRoleParams) (*ConversationRole, error)
+	DeleteRole(sid string) error
+}
+
+type conversationsImpl struct {
+	client *clientImpl
+}
+
+// Conversations is a group of APIs related to Twilio Conversations.
+func (client *clientImpl) Conversations() Conversations {
+	return &conversationsImpl{
+		client: client,
+	}
+}
+
+func (impl *conversationsImpl) url(path ...string) string {
+	u := impl.client.opts.ConversationsBaseURL + "/v1"
+	for _, p := range path {
+		u += "/" + url.PathEscape(p)
+	}
+
+	return u
+}
+
+// ConversationTimers are the timers that will change the state of a
+// conversation.
+type ConversationTimers struct {
+	DateInactive *time.Time `json:"date_inactive"`
+	DateClosed   *time.Time `json:"date_closed"`
+}
+
+// Conversation is a Twilio Conversations conversation.
+type Conversation struct {
+	SID                 string             `json:"sid"`
+	AccountSID          string             `json:"account_sid"`
+	ChatServiceSID      string             `json:"chat_service_sid"`
+	MessagingServiceSID string             `json:"messaging_service_sid"`
+	FriendlyName        string             `json:"friendly_name"`
+	UniqueName          string             `json:"unique_name"`
+	Attributes          string             `json:"attributes"`
+	State               string             `json:"state"`
+	Timers              ConversationTimers `json:"timers"`
+	DateCreated         time.Time          `json:"date_created"`
+	DateUpdated         time.Time          `json:"date_updated"`
+	URL                 string             `json:"url"`
+	Links               map[string]string  `json:"links"`
+}
+
+// ConversationParams are the parameters used to create or update a
+// conversation. Empty values are not sent.
+type ConversationParams struct {
+	FriendlyName        string
+	UniqueName          string
+	Attributes          string
+	MessagingServiceSID string
+	State               string
+	TimersInactive      time.Duration
+	TimersClosed        time.Duration
+}
+
+func (params *ConversationParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "FriendlyName", params.FriendlyName)
+	setValue(v, "UniqueName", params.UniqueName)
+	setValue(v, "Attributes", params.Attributes)
+	setValue(v, "MessagingServiceSid", params.MessagingServiceSID)
+	setValue(v, "State", params.State)
+	setDuration(v, "Timers.Inactive", params.TimersInactive)
+	setDuration(v, "Timers.Closed", params.TimersClosed)
+
+	return v
+}
+
+type conversationPage struct {
+	Conversations []*Conversation `json:"conversations"`
+	Meta          pageMeta        `json:"meta"`
+}
+
+func (p *conversationPage) nextPageURL() string {
+	return p.Meta.NextPageURL
+}
+
+// ConversationIterator iterates over a list of conversations, fetching pages
+// as needed.
+type ConversationIterator struct {
+	pager   pager
+	items   []*Conversation
+	current *Conversation
+}
+
+// Next advances to the next conversation. It returns false when there are no
+// more conversations or an error occurred.
+func (it *ConversationIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conversationPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Conversations
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Conversation returns the current conversation.
+func (it *ConversationIterator) Conversation() *Conversation {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConversationIterator) Err() error {
+	return it.pager.err
+}
+
+func (impl *conversationsImpl) CreateConversation(
+	params *ConversationParams,
+) (*Conversation, error) {
+	var conversation Conversation
+	err := impl.client.create(impl.url("Conversations"), params.values(), &conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+func (impl *conversationsImpl) FetchConversation(sid string) (*Conversation, error) {
+	var conversation Conversation
+	err := impl.client.fetch(impl.url("Conversations", sid), &conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+func (impl *conversationsImpl) ListConversations() *ConversationIterator {
+	return &ConversationIterator{
+		pager: newPager(impl.client, impl.url("Conversations")),
+	}
+}
+
+func (impl *conversationsImpl) UpdateConversation(
+	sid string,
+	params *ConversationParams,
+) (*Conversation, error) {
+	var conversation Conversation
+	err := impl.client.update(
+		impl.url("Conversations", sid),
+		params.values(),
+		&conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+func (impl *conversationsImpl) DeleteConversation(sid string) error {
+	return impl.client.remove(impl.url("Conversations", sid))
+}
@@ -0,0 +1,164 @@
+package twilio
+
+import (
+	"net/url"
+	"time"
+)
+
+// ConversationMedia is media attached to a conversation message.
+type ConversationMedia struct {
+	SID         string `json:"sid"`
+	ContentType string `json:"content_type"`
+	Filename    string `json:"filename"`
+	Size        int    `json:"size"`
+}
+
+// ConversationMessage is a message in a conversation.
+type ConversationMessage struct {
+	SID             string                 `json:"sid"`
+	AccountSID      string                 `json:"account_sid"`
+	ChatServiceSID  string                 `json:"chat_service_sid"`
+	ConversationSID string                 `json:"conversation_sid"`
+	Index           int                    `json:"index"`
+	Author          string                 `json:"author"`
+	Body            string                 `json:"body"`
+	Media           []*ConversationMedia   `json:"media"`
+	Attributes      string                 `json:"attributes"`
+	ParticipantSID  string                 `json:"participant_sid"`
+	Delivery        map[string]interface{} `json:"delivery"`
+	DateCreated     time.Time              `json:"date_created"`
+	DateUpdated     time.Time              `json:"date_updated"`
+	URL             string                 `json:"url"`
+}
+
+// ConversationMessageParams are the parameters used to send or update a
+// message. Empty values are not sent.
+type ConversationMessageParams struct {
+	Author     string
+	Body       string
+	Attributes string
+	MediaSID   string
+}
+
+func (params *ConversationMessageParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "Author", params.Author)
+	setValue(v, "Body", params.Body)
+	setValue(v, "Attributes", params.Attributes)
+	setValue(v, "MediaSid", params.MediaSID)
+
+	return v
+}
+
+type conversationMessagePage struct {
+	Messages []*ConversationMessage `json:"messages"`
+	Meta     pageMeta               `json:"meta"`
+}
+
+func (p *conversationMessagePage) nextPageURL() string {
+	return p.Meta.NextPageURL
+}
+
+// ConversationMessageIterator iterates over a list of messages, fetching
+// pages as needed.
+type ConversationMessageIterator struct {
+	pager   pager
+	items   []*ConversationMessage
+	current *ConversationMessage
+}
+
+// Next advances to the next message. It returns false when there are no more
+// messages or an error occurred.
+func (it *ConversationMessageIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conversationMessagePage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Messages
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Message returns the current message.
+func (it *ConversationMessageIterator) Message() *ConversationMessage {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConversationMessageIterator) Err() error {
+	return it.pager.err
+}
+
+func (impl *conversationsImpl) CreateMessage(
+	conversationSID string,
+	params *ConversationMessageParams,
+) (*ConversationMessage, error) {
+	var message ConversationMessage
+	err := impl.client.create(
+		impl.url("Conversations", conversationSID, "Messages"),
+		params.values(),
+		&message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (impl *conversationsImpl) FetchMessage(
+	conversationSID string,
+	sid string,
+) (*ConversationMessage, error) {
+	var message ConversationMessage
+	err := impl.client.fetch(
+		impl.url("Conversations", conversationSID, "Messages", sid),
+		&message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (impl *conversationsImpl) ListMessages(
+	conversationSID string,
+) *ConversationMessageIterator {
+	return &ConversationMessageIterator{
+		pager: newPager(
+			impl.client,
+			impl.url("Conversations", conversationSID, "Messages")),
+	}
+}
+
+func (impl *conversationsImpl) UpdateMessage(
+	conversationSID string,
+	sid string,
+	params *ConversationMessageParams,
+) (*ConversationMessage, error) {
+	var message ConversationMessage
+	err := impl.client.update(
+		impl.url("Conversations", conversationSID, "Messages", sid),
+		params.values(),
+		&message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (impl *conversationsImpl) DeleteMessage(
+	conversationSID string,
+	sid string,
+) error {
+	return impl.client.remove(
+		impl.url("Conversations", conversationSID, "Messages", sid))
+}
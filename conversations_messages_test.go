@@ -0,0 +1,76 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMessageUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1/Messages", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", r.Form.Get("Author"))
+		assert.Equal(t, "Hello!", r.Form.Get("Body"))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+	"sid": "IM1",
+	"conversation_sid": "CH1",
+	"index": 3,
+	"author": "alice",
+	"body": "Hello!",
+	"media": null
+}`))
+	})
+
+	message, err := newConversationsUsingMockServer(server.URL).CreateMessage(
+		"CH1",
+		&ConversationMessageParams{
+			Author: "alice",
+			Body:   "Hello!",
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ConversationMessage{
+		SID:             "IM1",
+		ConversationSID: "CH1",
+		Index:           3,
+		Author:          "alice",
+		Body:            "Hello!",
+	}, message)
+}
+
+func TestListMessagesWithUnexpectedStatusCodeUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1/Messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Page") == "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{
+	"messages": [{"sid": "IM1"}],
+	"meta": {"next_page_url": "http://%s/v1/Conversations/CH1/Messages?Page=1"}
+}`, r.Host)
+	})
+
+	it := newConversationsUsingMockServer(server.URL).ListMessages("CH1")
+
+	assert.True(t, it.Next())
+	assert.Equal(t, "IM1", it.Message().SID)
+	assert.False(t, it.Next())
+
+	expectedError := "Unexpected response. Expected 200 but found 500"
+	assert.Equal(t, it.Err().Error(), expectedError)
+}
@@ -0,0 +1,173 @@
+package twilio
+
+import (
+	"net/url"
+	"time"
+)
+
+// ConversationMessagingBinding describes how a non-chat participant, such as
+// an SMS user, is connected to a conversation.
+type ConversationMessagingBinding struct {
+	Type             string `json:"type"`
+	Address          string `json:"address"`
+	ProxyAddress     string `json:"proxy_address"`
+	ProjectedAddress string `json:"projected_address"`
+}
+
+// ConversationParticipant is a participant in a conversation.
+type ConversationParticipant struct {
+	SID                  string                        `json:"sid"`
+	AccountSID           string                        `json:"account_sid"`
+	ChatServiceSID       string                        `json:"chat_service_sid"`
+	ConversationSID      string                        `json:"conversation_sid"`
+	Identity             string                        `json:"identity"`
+	Attributes           string                        `json:"attributes"`
+	MessagingBinding     *ConversationMessagingBinding `json:"messaging_binding"`
+	RoleSID              string                        `json:"role_sid"`
+	LastReadMessageIndex *int                          `json:"last_read_message_index"`
+	LastReadTimestamp    string                        `json:"last_read_timestamp"`
+	DateCreated          time.Time                     `json:"date_created"`
+	DateUpdated          time.Time                     `json:"date_updated"`
+	URL                  string                        `json:"url"`
+}
+
+// ConversationParticipantParams are the parameters used to add or update a
+// participant. Chat participants are identified by Identity and SMS
+// participants by MessagingBindingAddress. Empty values are not sent.
+type ConversationParticipantParams struct {
+	Identity                         string
+	Attributes                       string
+	RoleSID                          string
+	MessagingBindingAddress          string
+	MessagingBindingProxyAddress     string
+	MessagingBindingProjectedAddress string
+
+	// LastReadMessageIndex is a pointer so that 0 can be sent.
+	LastReadMessageIndex *int
+}
+
+func (params *ConversationParticipantParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "Identity", params.Identity)
+	setValue(v, "Attributes", params.Attributes)
+	setValue(v, "RoleSid", params.RoleSID)
+	setValue(v, "MessagingBinding.Address", params.MessagingBindingAddress)
+	setValue(v, "MessagingBinding.ProxyAddress", params.MessagingBindingProxyAddress)
+	setValue(v, "MessagingBinding.ProjectedAddress", params.MessagingBindingProjectedAddress)
+	setOptionalInt(v, "LastReadMessageIndex", params.LastReadMessageIndex)
+
+	return v
+}
+
+type conversationParticipantPage struct {
+	Participants []*ConversationParticipant `json:"participants"`
+	Meta         pageMeta                   `json:"meta"`
+}
+
+func (p *conversationParticipantPage) nextPageURL() string {
+	return p.Meta.NextPageURL
+}
+
+// ConversationParticipantIterator iterates over a list of participants,
+// fetching pages as needed.
+type ConversationParticipantIterator struct {
+	pager   pager
+	items   []*ConversationParticipant
+	current *ConversationParticipant
+}
+
+// Next advances to the next participant. It returns false when there are no
+// more participants or an error occurred.
+func (it *ConversationParticipantIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conversationParticipantPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Participants
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Participant returns the current participant.
+func (it *ConversationParticipantIterator) Participant() *ConversationParticipant {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConversationParticipantIterator) Err() error {
+	return it.pager.err
+}
+
+func (impl *conversationsImpl) CreateParticipant(
+	conversationSID string,
+	params *ConversationParticipantParams,
+) (*ConversationParticipant, error) {
+	var participant ConversationParticipant
+	err := impl.client.create(
+		impl.url("Conversations", conversationSID, "Participants"),
+		params.values(),
+		&participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (impl *conversationsImpl) FetchParticipant(
+	conversationSID string,
+	sid string,
+) (*ConversationParticipant, error) {
+	var participant ConversationParticipant
+	err := impl.client.fetch(
+		impl.url("Conversations", conversationSID, "Participants", sid),
+		&participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (impl *conversationsImpl) ListParticipants(
+	conversationSID string,
+) *ConversationParticipantIterator {
+	return &ConversationParticipantIterator{
+		pager: newPager(
+			impl.client,
+			impl.url("Conversations", conversationSID, "Participants")),
+	}
+}
+
+func (impl *conversationsImpl) UpdateParticipant(
+	conversationSID string,
+	sid string,
+	params *ConversationParticipantParams,
+) (*ConversationParticipant, error) {
+	var participant ConversationParticipant
+	err := impl.client.update(
+		impl.url("Conversations", conversationSID, "Participants", sid),
+		params.values(),
+		&participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+func (impl *conversationsImpl) DeleteParticipant(
+	conversationSID string,
+	sid string,
+) error {
+	return impl.client.remove(
+		impl.url("Conversations", conversationSID, "Participants", sid))
+}
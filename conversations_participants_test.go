@@ -0,0 +1,95 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateParticipantUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1/Participants", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "+15108675310", r.Form.Get("MessagingBinding.Address"))
+		assert.Equal(t, "+14155552345", r.Form.Get("MessagingBinding.ProxyAddress"))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+	"sid": "MB1",
+	"conversation_sid": "CH1",
+	"identity": null,
+	"messaging_binding": {
+		"type": "sms",
+		"address": "+15108675310",
+		"proxy_address": "+14155552345"
+	},
+	"last_read_message_index": null
+}`))
+	})
+
+	participant, err := newConversationsUsingMockServer(server.URL).CreateParticipant(
+		"CH1",
+		&ConversationParticipantParams{
+			MessagingBindingAddress:      "+15108675310",
+			MessagingBindingProxyAddress: "+14155552345",
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ConversationParticipant{
+		SID:             "MB1",
+		ConversationSID: "CH1",
+		MessagingBinding: &ConversationMessagingBinding{
+			Type:         "sms",
+			Address:      "+15108675310",
+			ProxyAddress: "+14155552345",
+		},
+	}, participant)
+}
+
+func TestListAndDeleteParticipantsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1/Participants", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{
+	"participants": [{"sid": "MB1", "identity": "alice"}, {"sid": "MB2", "identity": "bob"}],
+	"meta": {"next_page_url": null}
+}`)
+	})
+
+	mux.HandleFunc("/v1/Conversations/CH1/Participants/MB2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	conversations := newConversationsUsingMockServer(server.URL)
+	it := conversations.ListParticipants("CH1")
+
+	var identities []string
+	for it.Next() {
+		identities = append(identities, it.Participant().Identity)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"alice", "bob"}, identities)
+
+	err := conversations.DeleteParticipant("CH1", "MB2")
+	assert.NoError(t, err)
+}
+
+func TestConversationParticipantParamsLastReadMessageIndex(t *testing.T) {
+	assert.Equal(t, url.Values{}, (&ConversationParticipantParams{}).values())
+
+	index := 0
+	v := (&ConversationParticipantParams{LastReadMessageIndex: &index}).values()
+	assert.Equal(t, url.Values{"LastReadMessageIndex": {"0"}}, v)
+}
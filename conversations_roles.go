@@ -0,0 +1,129 @@
+package twilio
+
+import (
+	"net/url"
+	"time"
+)
+
+// ConversationRole is a set of permissions granted to participants.
+type ConversationRole struct {
+	SID            string    `json:"sid"`
+	AccountSID     string    `json:"account_sid"`
+	ChatServiceSID string    `json:"chat_service_sid"`
+	FriendlyName   string    `json:"friendly_name"`
+	Type           string    `json:"type"`
+	Permissions    []string  `json:"permissions"`
+	DateCreated    time.Time `json:"date_created"`
+	DateUpdated    time.Time `json:"date_updated"`
+	URL            string    `json:"url"`
+}
+
+// ConversationRoleParams are the parameters used to create or update a role.
+// FriendlyName and Type, which is conversation or service, can only be set
+// when creating a role. Updating a role replaces all of its permissions.
+type ConversationRoleParams struct {
+	FriendlyName string
+	Type         string
+	Permissions  []string
+}
+
+func (params *ConversationRoleParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "FriendlyName", params.FriendlyName)
+	setValue(v, "Type", params.Type)
+	setValues(v, "Permission", params.Permissions)
+
+	return v
+}
+
+type conversationRolePage struct {
+	Roles []*ConversationRole `json:"roles"`
+	Meta  pageMeta            `json:"meta"`
+}
+
+func (p *conversationRolePage) nextPageURL() string {
+	return p.Meta.NextPageURL
+}
+
+// ConversationRoleIterator iterates over a list of roles, fetching pages as
+// needed.
+type ConversationRoleIterator struct {
+	pager   pager
+	items   []*ConversationRole
+	current *ConversationRole
+}
+
+// Next advances to the next role. It returns false when there are no more
+// roles or an error occurred.
+func (it *ConversationRoleIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conversationRolePage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Roles
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Role returns the current role.
+func (it *ConversationRoleIterator) Role() *ConversationRole {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConversationRoleIterator) Err() error {
+	return it.pager.err
+}
+
+func (impl *conversationsImpl) CreateRole(
+	params *ConversationRoleParams,
+) (*ConversationRole, error) {
+	var role ConversationRole
+	err := impl.client.create(impl.url("Roles"), params.values(), &role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (impl *conversationsImpl) FetchRole(sid string) (*ConversationRole, error) {
+	var role ConversationRole
+	err := impl.client.fetch(impl.url("Roles", sid), &role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (impl *conversationsImpl) ListRoles() *ConversationRoleIterator {
+	return &ConversationRoleIterator{
+		pager: newPager(impl.client, impl.url("Roles")),
+	}
+}
+
+func (impl *conversationsImpl) UpdateRole(
+	sid string,
+	params *ConversationRoleParams,
+) (*ConversationRole, error) {
+	var role ConversationRole
+	err := impl.client.update(impl.url("Roles", sid), params.values(), &role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (impl *conversationsImpl) DeleteRole(sid string) error {
+	return impl.client.remove(impl.url("Roles", sid))
+}
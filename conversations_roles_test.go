@@ -0,0 +1,61 @@
+// +build unit
+
+package twilio
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndUpdateRoleUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Roles", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "agent", r.Form.Get("FriendlyName"))
+		assert.Equal(t, "conversation", r.Form.Get("Type"))
+		assert.Equal(t, []string{"sendMessage", "leaveConversation"}, r.Form["Permission"])
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+	"sid": "RL1",
+	"friendly_name": "agent",
+	"type": "conversation",
+	"permissions": ["sendMessage", "leaveConversation"]
+}`))
+	})
+
+	mux.HandleFunc("/v1/Roles/RL1", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sendMessage"}, r.Form["Permission"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "RL1", "permissions": ["sendMessage"]}`))
+	})
+
+	conversations := newConversationsUsingMockServer(server.URL)
+
+	role, err := conversations.CreateRole(&ConversationRoleParams{
+		FriendlyName: "agent",
+		Type:         "conversation",
+		Permissions:  []string{"sendMessage", "leaveConversation"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ConversationRole{
+		SID:          "RL1",
+		FriendlyName: "agent",
+		Type:         "conversation",
+		Permissions:  []string{"sendMessage", "leaveConversation"},
+	}, role)
+
+	role, err = conversations.UpdateRole("RL1", &ConversationRoleParams{
+		Permissions: []string{"sendMessage"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sendMessage"}, role.Permissions)
+}
@@ -0,0 +1,182 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newConversationsUsingMockServer(url string) Conversations {
+	opts := NewOptions("sid", "token")
+	opts.ConversationsBaseURL = url
+	return NewClient(opts).Conversations()
+}
+
+func TestCreateConversationUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "sid", username)
+		assert.Equal(t, "token", password)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Support", r.Form.Get("FriendlyName"))
+		assert.Equal(t, "PT600S", r.Form.Get("Timers.Inactive"))
+		assert.Empty(t, r.Form.Get("UniqueName"))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+	"sid": "CHXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+	"account_sid": "sid",
+	"chat_service_sid": "ISXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+	"friendly_name": "Support",
+	"unique_name": null,
+	"attributes": "{}",
+	"state": "active",
+	"timers": {
+		"date_inactive": "2015-12-16T22:28:37Z",
+		"date_closed": null
+	},
+	"date_created": "2015-12-16T22:18:37Z",
+	"date_updated": "2015-12-16T22:18:38Z",
+	"url": "https://conversations.twilio.com/v1/Conversations/CHXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX"
+}`))
+	})
+
+	conversation, err := newConversationsUsingMockServer(server.URL).CreateConversation(
+		&ConversationParams{
+			FriendlyName:   "Support",
+			TimersInactive: 10 * time.Minute,
+		})
+
+	dateInactive := time.Date(2015, 12, 16, 22, 28, 37, 0, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, &Conversation{
+		SID:            "CHXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+		AccountSID:     "sid",
+		ChatServiceSID: "ISXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+		FriendlyName:   "Support",
+		Attributes:     "{}",
+		State:          "active",
+		Timers: ConversationTimers{
+			DateInactive: &dateInactive,
+		},
+		DateCreated: time.Date(2015, 12, 16, 22, 18, 37, 0, time.UTC),
+		DateUpdated: time.Date(2015, 12, 16, 22, 18, 38, 0, time.UTC),
+		URL:         "https://conversations.twilio.com/v1/Conversations/CHXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+	}, conversation)
+}
+
+func TestCreateConversationWithUnexpectedStatusCodeUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := newConversationsUsingMockServer(server.URL).CreateConversation(nil)
+
+	expectedError := "Unexpected response. Expected 201 but found 400"
+	assert.Equal(t, err.Error(), expectedError)
+}
+
+func TestFetchUpdateAndDeleteConversationUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "CH1", "state": "active"}`))
+		case http.MethodPost:
+			assert.Equal(t, "closed", r.Form.Get("State"))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "CH1", "state": "closed"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	conversations := newConversationsUsingMockServer(server.URL)
+
+	conversation, err := conversations.FetchConversation("CH1")
+	assert.NoError(t, err)
+	assert.Equal(t, "active", conversation.State)
+
+	conversation, err = conversations.UpdateConversation("CH1", &ConversationParams{
+		State: "closed",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", conversation.State)
+
+	err = conversations.DeleteConversation("CH1")
+	assert.NoError(t, err)
+}
+
+func TestListConversationsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("Page") == "1" {
+			fmt.Fprint(w, `{
+	"conversations": [{"sid": "CH3"}],
+	"meta": {"page": 1, "page_size": 2, "next_page_url": null, "key": "conversations"}
+}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{
+	"conversations": [{"sid": "CH1"}, {"sid": "CH2"}],
+	"meta": {
+		"page": 0,
+		"page_size": 2,
+		"next_page_url": "%s/v1/Conversations?PageSize=2&Page=1",
+		"key": "conversations"
+	}
+}`, "http://"+r.Host)
+	})
+
+	it := newConversationsUsingMockServer(server.URL).ListConversations()
+
+	var sids []string
+	for it.Next() {
+		sids = append(sids, it.Conversation().SID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"CH1", "CH2", "CH3"}, sids)
+}
+
+func TestListConversationsWithInvalidJSONUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "invalid JSON")
+	})
+
+	it := newConversationsUsingMockServer(server.URL).ListConversations()
+
+	assert.False(t, it.Next())
+	expectedError := "invalid character 'i' looking for beginning of value"
+	assert.Equal(t, it.Err().Error(), expectedError)
+}
@@ -0,0 +1,169 @@
+package twilio
+
+import (
+	"net/url"
+	"time"
+)
+
+// ConversationWebhookConfiguration is the configuration of a conversation
+// scoped webhook.
+type ConversationWebhookConfiguration struct {
+	URL         string   `json:"url"`
+	Method      string   `json:"method"`
+	Filters     []string `json:"filters"`
+	Triggers    []string `json:"triggers"`
+	FlowSID     string   `json:"flow_sid"`
+	ReplayAfter *int     `json:"replay_after"`
+}
+
+// ConversationWebhook is a webhook scoped to a single conversation.
+type ConversationWebhook struct {
+	SID             string                           `json:"sid"`
+	AccountSID      string                           `json:"account_sid"`
+	ChatServiceSID  string                           `json:"chat_service_sid"`
+	ConversationSID string                           `json:"conversation_sid"`
+	Target          string                           `json:"target"`
+	Configuration   ConversationWebhookConfiguration `json:"configuration"`
+	DateCreated     time.Time                        `json:"date_created"`
+	DateUpdated     time.Time                        `json:"date_updated"`
+	URL             string                           `json:"url"`
+}
+
+// ConversationWebhookParams are the parameters used to create or update a
+// webhook. Target can only be set when creating a webhook and is one of
+// webhook, trigger or studio. Empty values are not sent.
+type ConversationWebhookParams struct {
+	Target                   string
+	ConfigurationURL         string
+	ConfigurationMethod      string
+	ConfigurationFilters     []string
+	ConfigurationTriggers    []string
+	ConfigurationFlowSID     string
+	ConfigurationReplayAfter int
+}
+
+func (params *ConversationWebhookParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "Target", params.Target)
+	setValue(v, "Configuration.Url", params.ConfigurationURL)
+	setValue(v, "Configuration.Method", params.ConfigurationMethod)
+	setValues(v, "Configuration.Filters", params.ConfigurationFilters)
+	setValues(v, "Configuration.Triggers", params.ConfigurationTriggers)
+	setValue(v, "Configuration.FlowSid", params.ConfigurationFlowSID)
+	setInt(v, "Configuration.ReplayAfter", params.ConfigurationReplayAfter)
+
+	return v
+}
+
+type conversationWebhookPage struct {
+	Webhooks []*ConversationWebhook `json:"webhooks"`
+	Meta     pageMeta               `json:"meta"`
+}
+
+func (p *conversationWebhookPage) nextPageURL() string {
+	return p.Meta.NextPageURL
+}
+
+// ConversationWebhookIterator iterates over a list of webhooks, fetching
+// pages as needed.
+type ConversationWebhookIterator struct {
+	pager   pager
+	items   []*ConversationWebhook
+	current *ConversationWebhook
+}
+
+// Next advances to the next webhook. It returns false when there are no more
+// webhooks or an error occurred.
+func (it *ConversationWebhookIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p conversationWebhookPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Webhooks
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Webhook returns the current webhook.
+func (it *ConversationWebhookIterator) Webhook() *ConversationWebhook {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConversationWebhookIterator) Err() error {
+	return it.pager.err
+}
+
+func (impl *conversationsImpl) CreateWebhook(
+	conversationSID string,
+	params *ConversationWebhookParams,
+) (*ConversationWebhook, error) {
+	var webhook ConversationWebhook
+	err := impl.client.create(
+		impl.url("Conversations", conversationSID, "Webhooks"),
+		params.values(),
+		&webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (impl *conversationsImpl) FetchWebhook(
+	conversationSID string,
+	sid string,
+) (*ConversationWebhook, error) {
+	var webhook ConversationWebhook
+	err := impl.client.fetch(
+		impl.url("Conversations", conversationSID, "Webhooks", sid),
+		&webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (impl *conversationsImpl) ListWebhooks(
+	conversationSID string,
+) *ConversationWebhookIterator {
+	return &ConversationWebhookIterator{
+		pager: newPager(
+			impl.client,
+			impl.url("Conversations", conversationSID, "Webhooks")),
+	}
+}
+
+func (impl *conversationsImpl) UpdateWebhook(
+	conversationSID string,
+	sid string,
+	params *ConversationWebhookParams,
+) (*ConversationWebhook, error) {
+	var webhook ConversationWebhook
+	err := impl.client.update(
+		impl.url("Conversations", conversationSID, "Webhooks", sid),
+		params.values(),
+		&webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (impl *conversationsImpl) DeleteWebhook(
+	conversationSID string,
+	sid string,
+) error {
+	return impl.client.remove(
+		impl.url("Conversations", conversationSID, "Webhooks", sid))
+}
@@ -0,0 +1,57 @@
+// +build unit
+
+package twilio
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhookUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/v1/Conversations/CH1/Webhooks", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "webhook", r.Form.Get("Target"))
+		assert.Equal(t, "https://example.com/hook", r.Form.Get("Configuration.Url"))
+		assert.Equal(t,
+			[]string{"onMessageAdded", "onParticipantAdded"},
+			r.Form["Configuration.Filters"])
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+	"sid": "WH1",
+	"conversation_sid": "CH1",
+	"target": "webhook",
+	"configuration": {
+		"url": "https://example.com/hook",
+		"method": "POST",
+		"filters": ["onMessageAdded", "onParticipantAdded"]
+	}
+}`))
+	})
+
+	webhook, err := newConversationsUsingMockServer(server.URL).CreateWebhook(
+		"CH1",
+		&ConversationWebhookParams{
+			Target:               "webhook",
+			ConfigurationURL:     "https://example.com/hook",
+			ConfigurationFilters: []string{"onMessageAdded", "onParticipantAdded"},
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ConversationWebhook{
+		SID:             "WH1",
+		ConversationSID: "CH1",
+		Target:          "webhook",
+		Configuration: ConversationWebhookConfiguration{
+			URL:     "https://example.com/hook",
+			Method:  "POST",
+			Filters: []string{"onMessageAdded", "onParticipantAdded"},
+		},
+	}, webhook)
+}
@@ -0,0 +1,439 @@
+package twilio
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DialerContactState is the state of a contact in a dialer campaign.
+type DialerContactState string
+
+const (
+	// DialerPending contacts are waiting to be called for the first time or
+	// to be retried.
+	DialerPending DialerContactState = "pending"
+
+	// DialerDialing contacts have a call that has not finished.
+	DialerDialing DialerContactState = "dialing"
+
+	// DialerAnswered contacts answered a call and will not be called again.
+	DialerAnswered DialerContactState = "answered"
+
+	// DialerFailed contacts could not be reached within the maximum number
+	// of attempts, or their call failed permanently.
+	DialerFailed DialerContactState = "failed"
+)
+
+// DialerContact is a phone number in a dialer campaign and the outcome of
+// calling it.
+type DialerContact struct {
+	To            string             `json:"to"`
+	State         DialerContactState `json:"state"`
+	Attempts      int                `json:"attempts"`
+	CallSID       string             `json:"call_sid,omitempty"`
+	Status        CallStatus         `json:"status,omitempty"`
+	AnsweredBy    AnsweredBy         `json:"answered_by,omitempty"`
+	Duration      int                `json:"duration,omitempty"`
+	DialedAt      time.Time          `json:"dialed_at"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	LastError     string             `json:"last_error,omitempty"`
+}
+
+// ErrDialerContactExists is returned when a phone number is added to a
+// dialer more than once.
+var ErrDialerContactExists = errors.New("Contact has already been added")
+
+// Dialer places a campaign of outbound calls while limiting the number of
+// concurrent calls and the rate at which calls are placed. Calls that are
+// busy or not answered are retried later, up to a maximum number of
+// attempts per phone number.
+//
+// The dialer learns how calls end from call status callbacks, so the call
+// parameters must set StatusCallback and the dialer's HandleCallStatus must
+// be added as a listener to the CallStatusHandler for that URL. A final
+// status that arrives before Twilio has returned the call is missed, and the
+// call is fetched once the status timeout has passed.
+type Dialer struct {
+	client Client
+	params CallParams
+
+	// MaxConcurrent is the maximum number of calls in progress at once.
+	MaxConcurrent int
+
+	// CallsPerSecond is the maximum rate at which calls are placed. Calls
+	// are not rate limited when it is zero.
+	CallsPerSecond float64
+
+	// MaxAttempts is the number of times a phone number is called before it
+	// is marked as failed.
+	MaxAttempts int
+
+	// RetryAfter is how long to wait before calling a phone number again
+	// after a call ends with the status. Calls that end with other statuses
+	// are not retried.
+	RetryAfter map[CallStatus]time.Duration
+
+	// Backoff returns how long to wait before retrying a call that Twilio
+	// could not create and has been attempted the given number of times.
+	Backoff func(attempts int) time.Duration
+
+	// StatusTimeout is how long to wait for a call's final status callback
+	// before fetching the call to find out how it ended. Calls are not
+	// fetched when it is zero.
+	StatusTimeout time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// OnResult is called when a contact is answered or fails permanently. It
+	// is called without the dialer's lock held, so it can call the dialer's
+	// methods.
+	OnResult func(*DialerContact)
+
+	mu         sync.Mutex
+	contacts   map[string]*DialerContact
+	order      []string
+	calls      map[string]string
+	tokens     float64
+	refilledAt time.Time
+}
+
+// NewDialer will create a dialer that places calls with the client using the
+// parameters, replacing To with each contact's phone number. By default, at
+// most 10 calls are in progress at once, one call is placed per second and
+// each phone number is called up to 3 times, retrying busy calls after 5
+// minutes and unanswered calls after 30 minutes.
+func NewDialer(client Client, params *CallParams) (*Dialer, error) {
+	_, err := params.values()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dialer{
+		client:         client,
+		params:         *params,
+		MaxConcurrent:  10,
+		CallsPerSecond: 1,
+		MaxAttempts:    3,
+		RetryAfter: map[CallStatus]time.Duration{
+			CallStatusBusy:     5 * time.Minute,
+			CallStatusNoAnswer: 30 * time.Minute,
+		},
+		Backoff:       exponentialBackoff,
+		StatusTimeout: 15 * time.Minute,
+		Now:           time.Now,
+		contacts:      map[string]*DialerContact{},
+		calls:         map[string]string{},
+	}, nil
+}
+
+// Add adds a phone number to be called by the next dispatch.
+func (d *Dialer) Add(to string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.contacts[to]; ok {
+		return ErrDialerContactExists
+	}
+
+	d.contacts[to] = &DialerContact{
+		To:            to,
+		State:         DialerPending,
+		NextAttemptAt: d.Now(),
+	}
+	d.order = append(d.order, to)
+	return nil
+}
+
+// Contact returns a copy of the contact with the phone number, or nil when
+// it has not been added.
+func (d *Dialer) Contact(to string) *DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok := d.contacts[to]
+	if !ok {
+		return nil
+	}
+
+	copied := *c
+	return &copied
+}
+
+// Contacts returns a copy of every contact in the order they were added.
+func (d *Dialer) Contacts() []*DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	contacts := make([]*DialerContact, 0, len(d.order))
+	for _, to := range d.order {
+		copied := *d.contacts[to]
+		contacts = append(contacts, &copied)
+	}
+
+	return contacts
+}
+
+// Done returns true when every contact has been answered or has failed.
+func (d *Dialer) Done() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, c := range d.contacts {
+		if c.State == DialerPending || c.State == DialerDialing {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Dispatch calls the contacts that are due, within the concurrency and rate
+// limits, and returns the number of calls that were attempted. Calls that
+// have not reported a final status within the status timeout are fetched
+// first. Twilio is called without holding the dialer's lock, so status
+// callbacks can be handled while calls are placed.
+func (d *Dialer) Dispatch() int {
+	var results []*DialerContact
+	for _, c := range d.stale() {
+		call, err := d.client.FetchCall(c.CallSID)
+		if err != nil || !call.Status.Final() {
+			// Errors are ignored so that the call is fetched again by the
+			// next dispatch.
+			continue
+		}
+
+		duration, _ := strconv.Atoi(call.Duration)
+		results = d.finishCall(results, c.CallSID, call.Status, call.AnsweredBy, duration)
+	}
+
+	reserved := d.reserve()
+	for _, c := range reserved {
+		params := d.params
+		params.To = c.To
+
+		call, err := d.client.CreateCall(&params)
+		results = d.dialed(results, c.To, call, err)
+	}
+
+	d.report(results)
+	return len(reserved)
+}
+
+// Run dispatches calls every interval until every contact has been answered
+// or has failed, or the context is done.
+func (d *Dialer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.Dispatch()
+		if d.Done() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// HandleCallStatus records how a campaign's call ended. It is a
+// CallStatusListener and ignores calls that were not placed by the dialer.
+func (d *Dialer) HandleCallStatus(ctx context.Context, event *CallStatusEvent) error {
+	if !event.CallStatus.Final() {
+		return nil
+	}
+
+	results := d.finishCall(nil, event.CallSID, event.CallStatus, event.AnsweredBy, event.CallDuration)
+	d.report(results)
+	return nil
+}
+
+// stale returns copies of the contacts whose calls have not reported a final
+// status within the status timeout.
+func (d *Dialer) stale() []*DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.StatusTimeout <= 0 {
+		return nil
+	}
+
+	now := d.Now()
+	var stale []*DialerContact
+	for _, to := range d.order {
+		c := d.contacts[to]
+		if c.State == DialerDialing && c.CallSID != "" && now.Sub(c.DialedAt) >= d.StatusTimeout {
+			copied := *c
+			stale = append(stale, &copied)
+		}
+	}
+
+	return stale
+}
+
+// reserve marks the contacts that are due as dialing, within the concurrency
+// and rate limits, and returns copies of them. Their calls are created by the
+// caller without holding the lock.
+func (d *Dialer) reserve() []*DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.Now()
+	d.refill(now)
+
+	dialing := 0
+	for _, c := range d.contacts {
+		if c.State == DialerDialing {
+			dialing++
+		}
+	}
+
+	var reserved []*DialerContact
+	for _, to := range d.order {
+		if d.MaxConcurrent > 0 && dialing >= d.MaxConcurrent {
+			break
+		}
+
+		if d.CallsPerSecond > 0 && d.tokens < 1 {
+			break
+		}
+
+		c := d.contacts[to]
+		if c.State != DialerPending || c.NextAttemptAt.After(now) {
+			continue
+		}
+
+		d.tokens--
+		dialing++
+		c.State = DialerDialing
+		c.Attempts++
+		c.DialedAt = now
+		c.CallSID = ""
+
+		copied := *c
+		reserved = append(reserved, &copied)
+	}
+
+	return reserved
+}
+
+// refill adds the tokens that have accumulated since the last refill,
+// allowing a burst of at most one second of calls.
+func (d *Dialer) refill(now time.Time) {
+	burst := d.CallsPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+
+	if d.refilledAt.IsZero() {
+		d.tokens = burst
+	} else if elapsed := now.Sub(d.refilledAt); elapsed > 0 {
+		d.tokens += elapsed.Seconds() * d.CallsPerSecond
+	}
+
+	if d.tokens > burst {
+		d.tokens = burst
+	}
+
+	d.refilledAt = now
+}
+
+// dialed records the outcome of creating a call to the contact and appends
+// the contact to the results when it has failed. Calls that Twilio could not
+// create are retried with a backoff when the error is temporary.
+func (d *Dialer) dialed(
+	results []*DialerContact,
+	to string,
+	call *Call,
+	err error,
+) []*DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c := d.contacts[to]
+	switch {
+	case err == nil:
+		c.CallSID = call.SID
+		c.Status = call.Status
+		c.AnsweredBy = ""
+		c.Duration = 0
+		c.LastError = ""
+		d.calls[call.SID] = c.To
+
+	case isTemporary(err) && c.Attempts < d.MaxAttempts:
+		c.State = DialerPending
+		c.NextAttemptAt = d.Now().Add(d.Backoff(c.Attempts))
+		c.LastError = err.Error()
+
+	default:
+		c.State = DialerFailed
+		c.LastError = err.Error()
+		results = appendDialerResult(results, c)
+	}
+
+	return results
+}
+
+// finishCall records the final status of a call placed by the dialer and
+// decides whether to call the contact again. The contact is appended to the
+// results when it has been answered or has failed. Calls that were not
+// placed by the dialer, or that have already finished, are ignored.
+func (d *Dialer) finishCall(
+	results []*DialerContact,
+	callSID string,
+	status CallStatus,
+	answeredBy AnsweredBy,
+	duration int,
+) []*DialerContact {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	to, ok := d.calls[callSID]
+	if !ok {
+		return results
+	}
+
+	c := d.contacts[to]
+	delete(d.calls, callSID)
+	c.Status = status
+	c.AnsweredBy = answeredBy
+	c.Duration = duration
+
+	if status == CallStatusCompleted {
+		c.State = DialerAnswered
+		return appendDialerResult(results, c)
+	}
+
+	if delay, ok := d.RetryAfter[status]; ok && c.Attempts < d.MaxAttempts {
+		c.State = DialerPending
+		c.NextAttemptAt = d.Now().Add(delay)
+		return results
+	}
+
+	c.State = DialerFailed
+	return appendDialerResult(results, c)
+}
+
+// report calls OnResult with each result. It must be called without holding
+// the lock so that OnResult can call the dialer's methods.
+func (d *Dialer) report(results []*DialerContact) {
+	if d.OnResult == nil {
+		return
+	}
+
+	for _, c := range results {
+		d.OnResult(c)
+	}
+}
+
+func appendDialerResult(results []*DialerContact, c *DialerContact) []*DialerContact {
+	copied := *c
+	return append(results, &copied)
+}
@@ -0,0 +1,216 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDialerServer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func newDialerUsingMockServer(t *testing.T) (*Dialer, *mockDialerServer, *time.Time, func()) {
+	mux, server, shutdown := setupMockServer()
+	mock := &mockDialerServer{}
+
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+
+		mock.mu.Lock()
+		mock.calls = append(mock.calls, r.PostForm.Get("To"))
+		sid := fmt.Sprintf("CA%d", len(mock.calls))
+		mock.mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"sid": "%s", "status": "queued"}`, sid)
+	})
+
+	now := time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC)
+	dialer, err := NewDialer(newCallsClientUsingMockServer(server.URL), &CallParams{
+		From:           "+14155552345",
+		URL:            "https://example.com/voice",
+		StatusCallback: "https://example.com/call-status",
+	})
+	assert.NoError(t, err)
+	dialer.Now = func() time.Time { return now }
+
+	return dialer, mock, &now, shutdown
+}
+
+func TestNewDialerRequiresInstructions(t *testing.T) {
+	_, err := NewDialer(newCallsClientUsingMockServer("http://127.0.0.1:0"), &CallParams{From: "+14155552345"})
+	assert.Equal(t, ErrCallInstructions, err)
+}
+
+func TestDialerLimitsConcurrencyAndRate(t *testing.T) {
+	dialer, mock, now, shutdown := newDialerUsingMockServer(t)
+	defer shutdown()
+
+	dialer.MaxConcurrent = 2
+	for _, to := range []string{"+15108675310", "+15108675311", "+15108675312"} {
+		assert.NoError(t, dialer.Add(to))
+	}
+	assert.Equal(t, ErrDialerContactExists, dialer.Add("+15108675310"))
+
+	assert.Equal(t, 1, dialer.Dispatch())
+	assert.Equal(t, 0, dialer.Dispatch())
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, 1, dialer.Dispatch())
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, 0, dialer.Dispatch())
+
+	assert.NoError(t, dialer.HandleCallStatus(context.Background(), &CallStatusEvent{
+		CallSID:      "CA1",
+		CallStatus:   CallStatusCompleted,
+		CallDuration: 42,
+		AnsweredBy:   AnsweredByHuman,
+	}))
+	assert.Equal(t, 1, dialer.Dispatch())
+
+	assert.Equal(t, []string{"+15108675310", "+15108675311", "+15108675312"}, mock.calls)
+	assert.Equal(t, &DialerContact{
+		To:            "+15108675310",
+		State:         DialerAnswered,
+		Attempts:      1,
+		CallSID:       "CA1",
+		Status:        CallStatusCompleted,
+		AnsweredBy:    AnsweredByHuman,
+		Duration:      42,
+		DialedAt:      time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC),
+		NextAttemptAt: time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC),
+	}, dialer.Contact("+15108675310"))
+	assert.False(t, dialer.Done())
+}
+
+func TestDialerRetriesBusyAndUnansweredCalls(t *testing.T) {
+	dialer, mock, now, shutdown := newDialerUsingMockServer(t)
+	defer shutdown()
+
+	dialer.MaxAttempts = 2
+	var results []*DialerContact
+	dialer.OnResult = func(c *DialerContact) {
+		results = append(results, c)
+	}
+
+	assert.NoError(t, dialer.Add("+15108675310"))
+	assert.Equal(t, 1, dialer.Dispatch())
+	assert.NoError(t, dialer.HandleCallStatus(context.Background(), &CallStatusEvent{
+		CallSID:    "CA1",
+		CallStatus: CallStatusBusy,
+	}))
+	assert.Equal(t, DialerPending, dialer.Contact("+15108675310").State)
+
+	*now = now.Add(4 * time.Minute)
+	assert.Equal(t, 0, dialer.Dispatch())
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, 1, dialer.Dispatch())
+	assert.NoError(t, dialer.HandleCallStatus(context.Background(), &CallStatusEvent{
+		CallSID:    "CA2",
+		CallStatus: CallStatusNoAnswer,
+	}))
+
+	assert.Equal(t, []string{"+15108675310", "+15108675310"}, mock.calls)
+	assert.Len(t, results, 1)
+	assert.Equal(t, DialerFailed, results[0].State)
+	assert.Equal(t, CallStatusNoAnswer, results[0].Status)
+	assert.Equal(t, 2, results[0].Attempts)
+	assert.True(t, dialer.Done())
+}
+
+func TestDialerFetchesCallsWithoutStatus(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid": "CA1", "status": "queued"}`))
+	})
+
+	status := "in-progress"
+	mux.HandleFunc("/Accounts/sid/Calls/CA1.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"sid": "CA1", "status": "%s", "duration": "30"}`, status)
+	})
+
+	now := time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC)
+	dialer, err := NewDialer(newCallsClientUsingMockServer(server.URL), &CallParams{
+		From: "+14155552345",
+		URL:  "https://example.com/voice",
+	})
+	assert.NoError(t, err)
+	dialer.Now = func() time.Time { return now }
+
+	assert.NoError(t, dialer.Add("+15108675310"))
+	assert.Equal(t, 1, dialer.Dispatch())
+
+	now = now.Add(15 * time.Minute)
+	dialer.Dispatch()
+	assert.Equal(t, DialerDialing, dialer.Contact("+15108675310").State)
+
+	status = "completed"
+	dialer.Dispatch()
+	contact := dialer.Contact("+15108675310")
+	assert.Equal(t, DialerAnswered, contact.State)
+	assert.Equal(t, 30, contact.Duration)
+}
+
+func TestDialerFailsPermanentErrors(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	dialer, err := NewDialer(newCallsClientUsingMockServer(server.URL), &CallParams{
+		From: "+14155552345",
+		URL:  "https://example.com/voice",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, dialer.Add("invalid"))
+	assert.Equal(t, 1, dialer.Dispatch())
+	assert.Equal(t, DialerFailed, dialer.Contact("invalid").State)
+	assert.NoError(t, dialer.Run(context.Background(), time.Millisecond))
+}
+
+func TestDialerCallsTwilioAndOnResultWithoutLock(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	var dialer *Dialer
+	var dialing []*DialerContact
+	mux.HandleFunc("/Accounts/sid/Calls.json", func(w http.ResponseWriter, r *http.Request) {
+		dialing = dialer.Contacts()
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	dialer, err := NewDialer(newCallsClientUsingMockServer(server.URL), &CallParams{
+		From: "+14155552345",
+		URL:  "https://example.com/voice",
+	})
+	assert.NoError(t, err)
+
+	var done bool
+	dialer.OnResult = func(c *DialerContact) {
+		done = dialer.Done()
+	}
+
+	assert.NoError(t, dialer.Add("invalid"))
+	assert.Equal(t, 1, dialer.Dispatch())
+	assert.Len(t, dialing, 1)
+	assert.Equal(t, DialerDialing, dialing[0].State)
+	assert.True(t, done)
+}
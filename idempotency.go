@@ -0,0 +1,296 @@
+package twilio
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idempotencyPending prefixes the value recorded for a key while a send is
+// in progress. It is followed by the Unix time the send started. Message
+// SIDs never start with it.
+const idempotencyPending = "pending:"
+
+// idempotencySIDPrefix prefixes the keys that record which idempotency key
+// owns a message SID.
+const idempotencySIDPrefix = "sid:"
+
+// IdempotencyStore records the SID of the message created for each
+// idempotency key. Before a message is sent, a pending marker is recorded in
+// place of the SID, so that a send whose outcome is unknown is reconciled
+// before it is retried. The idempotency key of each message is also recorded
+// under a key made of "sid:" and the message SID, so idempotency keys should
+// not start with "sid:".
+type IdempotencyStore interface {
+	// Get returns the message SID recorded for a key and whether one was
+	// found.
+	Get(key string) (string, bool, error)
+
+	// Put records the message SID for a key.
+	Put(key string, sid string) error
+
+	// Delete removes a key.
+	Delete(key string) error
+}
+
+// MemoryIdempotencyStore keeps idempotency keys in memory.
+type MemoryIdempotencyStore struct {
+	mu   sync.Mutex
+	sids map[string]string
+}
+
+// NewMemoryIdempotencyStore will create an empty in-memory store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		sids: map[string]string{},
+	}
+}
+
+// Get returns the message SID recorded for a key and whether one was found.
+func (store *MemoryIdempotencyStore) Get(key string) (string, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	sid, ok := store.sids[key]
+	return sid, ok, nil
+}
+
+// Put records the message SID for a key.
+func (store *MemoryIdempotencyStore) Put(key string, sid string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.sids[key] = sid
+	return nil
+}
+
+// Delete removes a key.
+func (store *MemoryIdempotencyStore) Delete(key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.sids, key)
+	return nil
+}
+
+// IdempotentSender sends messages at most once per idempotency key. Twilio
+// does not support idempotent message creation, so when a send fails in a
+// way that leaves its outcome unknown, such as a timeout, the sender lists
+// the recent messages to the recipient to find out whether the message was
+// created before sending it again.
+type IdempotentSender struct {
+	client Client
+	store  IdempotencyStore
+
+	// ClockSkew is how long before a send started to look for the message it
+	// created, allowing for differences between the local clock and
+	// Twilio's.
+	ClockSkew time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	locks keyLocks
+}
+
+// NewIdempotentSender will create a sender that records idempotency keys in
+// the store.
+func NewIdempotentSender(client Client, store IdempotencyStore) *IdempotentSender {
+	return &IdempotentSender{
+		client:    client,
+		store:     store,
+		ClockSkew: time.Minute,
+		Now:       time.Now,
+	}
+}
+
+// SendSMSMessage sends a message unless a message has already been sent with
+// the same idempotency key, in which case that message is returned. When an
+// earlier send with the key has an unknown outcome, the recent messages are
+// checked for it before it is sent again.
+func (s *IdempotentSender) SendSMSMessage(
+	idempotencyKey string,
+	from string,
+	to string,
+	body string,
+) (*SMSSendMessageResponse, error) {
+	unlock := s.locks.lock(idempotencyKey)
+	defer unlock()
+
+	sid, ok, err := s.store.Get(idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		started, pending := parseIdempotencyPending(sid)
+		if !pending {
+			return s.client.FetchSMSMessage(sid)
+		}
+
+		resp, err := s.reconcile(idempotencyKey, started.Add(-s.ClockSkew), from, to, body)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp != nil {
+			return resp, s.record(idempotencyKey, resp.SID)
+		}
+	}
+
+	now := s.Now()
+	err = s.store.Put(idempotencyKey, idempotencyPending+strconv.FormatInt(now.Unix(), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	since := now.Add(-s.ClockSkew)
+	resp, err := s.client.SendSMSMessage(from, to, body)
+	ambiguous := err != nil && isAmbiguous(err)
+	if ambiguous {
+		resp, err = s.reconcile(idempotencyKey, since, from, to, body)
+		if err == nil && resp == nil {
+			resp, err = s.client.SendSMSMessage(from, to, body)
+			ambiguous = err != nil && isAmbiguous(err)
+		}
+	}
+
+	if err != nil {
+		// The pending marker is kept unless Twilio rejected every attempt, so
+		// that the next send with the key is reconciled first.
+		if !ambiguous {
+			deleteErr := s.store.Delete(idempotencyKey)
+			if deleteErr != nil {
+				return nil, deleteErr
+			}
+		}
+
+		return nil, err
+	}
+
+	err = s.record(idempotencyKey, resp.SID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Recover looks for a message created by an earlier attempt to send with the
+// idempotency key, such as one interrupted by a restart, and records it. It
+// returns nil when no message has been created since the given time.
+func (s *IdempotentSender) Recover(
+	idempotencyKey string,
+	from string,
+	to string,
+	body string,
+	since time.Time,
+) (*SMSSendMessageResponse, error) {
+	unlock := s.locks.lock(idempotencyKey)
+	defer unlock()
+
+	sid, ok, err := s.store.Get(idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		started, pending := parseIdempotencyPending(sid)
+		if !pending {
+			return s.client.FetchSMSMessage(sid)
+		}
+
+		if started = started.Add(-s.ClockSkew); started.After(since) {
+			since = started
+		}
+	}
+
+	resp, err := s.reconcile(idempotencyKey, since, from, to, body)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+
+	err = s.record(idempotencyKey, resp.SID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// record records the message SID for the idempotency key and the key that
+// owns the SID.
+func (s *IdempotentSender) record(idempotencyKey string, sid string) error {
+	err := s.store.Put(idempotencySIDPrefix+sid, idempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(idempotencyKey, sid)
+}
+
+// reconcile looks for a message to the recipient created since the given
+// time with the same sender and body. Messages recorded for other
+// idempotency keys are skipped. It returns nil when there is none.
+func (s *IdempotentSender) reconcile(
+	idempotencyKey string,
+	since time.Time,
+	from string,
+	to string,
+	body string,
+) (*SMSSendMessageResponse, error) {
+	it := s.client.ListSMSMessages(&SMSListMessagesParams{
+		To:       to,
+		PageSize: 50,
+	})
+
+	for it.Next() {
+		msg := it.Message()
+		created, err := time.Parse(time.RFC1123Z, msg.DateCreated)
+		if err != nil {
+			return nil, err
+		}
+
+		// Messages are listed most recent first.
+		if created.Before(since.Truncate(time.Second)) {
+			break
+		}
+
+		if msg.From != from || msg.Body != body {
+			continue
+		}
+
+		owner, ok, err := s.store.Get(idempotencySIDPrefix + msg.SID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok || owner == idempotencyKey {
+			return msg, nil
+		}
+	}
+
+	return nil, it.Err()
+}
+
+// parseIdempotencyPending returns the time a pending send started and
+// whether the value is a pending marker.
+func parseIdempotencyPending(value string) (time.Time, bool) {
+	if !strings.HasPrefix(value, idempotencyPending) {
+		return time.Time{}, false
+	}
+
+	unix, _ := strconv.ParseInt(strings.TrimPrefix(value, idempotencyPending), 10, 64)
+	return time.Unix(unix, 0), true
+}
+
+// isAmbiguous returns true for errors that leave it unknown whether Twilio
+// created the message.
+func isAmbiguous(err error) bool {
+	if err, ok := err.(*UnexpectedResponseError); ok {
+		return err.StatusCode >= 500
+	}
+
+	return true
+}
@@ -0,0 +1,320 @@
+// +build unit
+
+package twilio
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMessagesServer struct {
+	posts        int
+	failPosts    int
+	createOnFail bool
+	postStatus   int
+	lists        int
+	failLists    int
+	messages     []string
+}
+
+func (m *mockMessagesServer) handle(mux *http.ServeMux) {
+	mux.HandleFunc("/Accounts/sid/Messages.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			m.lists++
+			if m.lists <= m.failLists {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"messages": [`))
+			for i := len(m.messages) - 1; i >= 0; i-- {
+				w.Write([]byte(m.messages[i]))
+				if i > 0 {
+					w.Write([]byte(","))
+				}
+			}
+			w.Write([]byte(`], "next_page_uri": null}`))
+			return
+		}
+
+		m.posts++
+		if m.posts <= m.failPosts {
+			if m.createOnFail {
+				m.messages = append(m.messages, `{
+	"sid": "SM1",
+	"from": "+14155552345",
+	"to": "+15108675310",
+	"body": "Hello!",
+	"date_created": "Sat, 01 Jun 2019 12:00:00 +0000"
+}`)
+			}
+
+			w.WriteHeader(m.postStatus)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "SM2", "status": "queued"}`))
+	})
+
+	mux.HandleFunc("/Accounts/sid/Messages/SM2.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "SM2", "status": "delivered"}`))
+	})
+}
+
+func newIdempotentSenderUsingMockServer(url string) *IdempotentSender {
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = url
+	sender := NewIdempotentSender(NewClient(opts), NewMemoryIdempotencyStore())
+	sender.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 12, 0, 30, 0, time.UTC)
+	}
+
+	return sender
+}
+
+func TestIdempotentSenderSendsOncePerKeyUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+	assert.NoError(t, err)
+	assert.Equal(t, "SM2", resp.SID)
+	assert.Equal(t, "queued", resp.Status)
+
+	resp, err = sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+	assert.NoError(t, err)
+	assert.Equal(t, "SM2", resp.SID)
+	assert.Equal(t, "delivered", resp.Status)
+
+	assert.Equal(t, 1, m.posts)
+}
+
+func TestIdempotentSenderReconcilesAmbiguousFailureUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:    1,
+		createOnFail: true,
+		postStatus:   http.StatusBadGateway,
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM1", resp.SID)
+	assert.Equal(t, 1, m.posts)
+}
+
+func TestIdempotentSenderResendsAfterAmbiguousFailureUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:  1,
+		postStatus: http.StatusBadGateway,
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM2", resp.SID)
+	assert.Equal(t, 2, m.posts)
+}
+
+func TestIdempotentSenderReconcilesPendingKeyBeforeResendingUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:    1,
+		createOnFail: true,
+		postStatus:   http.StatusBadGateway,
+		failLists:    1,
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	_, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	expectedError := "Unexpected response. Expected 200 but found 500"
+	assert.Equal(t, expectedError, err.Error())
+
+	sid, ok, err := sender.store.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "pending:1559390430", sid)
+
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM1", resp.SID)
+	assert.Equal(t, 1, m.posts)
+	assert.Equal(t, 2, m.lists)
+
+	sid, _, _ = sender.store.Get("key")
+	assert.Equal(t, "SM1", sid)
+}
+
+func TestIdempotentSenderDoesNotAdoptEarlierMessageUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:  1,
+		postStatus: http.StatusBadGateway,
+		messages: []string{`{
+	"sid": "SM0",
+	"from": "+14155552345",
+	"to": "+15108675310",
+	"body": "Hello!",
+	"date_created": "Sat, 01 Jun 2019 11:55:30 +0000"
+}`},
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM2", resp.SID)
+	assert.Equal(t, 2, m.posts)
+}
+
+func TestIdempotentSenderSkipsMessageOfOtherKeyUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:  1,
+		postStatus: http.StatusBadGateway,
+		messages: []string{`{
+	"sid": "SM1",
+	"from": "+14155552345",
+	"to": "+15108675310",
+	"body": "Hello!",
+	"date_created": "Sat, 01 Jun 2019 12:00:00 +0000"
+}`},
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	assert.NoError(t, sender.record("other", "SM1"))
+
+	resp, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM2", resp.SID)
+	assert.Equal(t, 2, m.posts)
+
+	owner, _, _ := sender.store.Get("sid:SM2")
+	assert.Equal(t, "key", owner)
+}
+
+type failingDeleteIdempotencyStore struct {
+	*MemoryIdempotencyStore
+}
+
+func (store *failingDeleteIdempotencyStore) Delete(key string) error {
+	return errors.New("Delete failed")
+}
+
+func TestIdempotentSenderReportsDeleteErrorUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:  1,
+		postStatus: http.StatusBadRequest,
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	sender.store = &failingDeleteIdempotencyStore{NewMemoryIdempotencyStore()}
+	_, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	assert.EqualError(t, err, "Delete failed")
+}
+
+func TestIdempotentSenderDoesNotReconcileRejectedMessageUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:  1,
+		postStatus: http.StatusBadRequest,
+	}
+	m.handle(mux)
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	_, err := sender.SendSMSMessage("key", "+14155552345", "+15108675310", "Hello!")
+
+	expectedError := "Unexpected response. Expected 200 but found 400"
+	assert.Equal(t, err.Error(), expectedError)
+	assert.Equal(t, 1, m.posts)
+
+	_, ok, err := sender.store.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestOutboxRecoversInterruptedSendUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	m := &mockMessagesServer{
+		failPosts:    1,
+		createOnFail: true,
+		postStatus:   http.StatusBadGateway,
+	}
+	m.handle(mux)
+
+	// Twilio created the message before the process stopped.
+	m.posts = 1
+	m.messages = []string{`{
+	"sid": "SM1",
+	"from": "+14155552345",
+	"to": "+15108675310",
+	"body": "Hello!",
+	"date_created": "Sat, 01 Jun 2019 12:00:00 +0000"
+}`}
+
+	sender := newIdempotentSenderUsingMockServer(server.URL)
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(sender.client, store)
+	outbox.Idempotency = sender
+
+	store.Put(&OutboxMessage{
+		ID:        "1",
+		From:      "+14155552345",
+		To:        "+15108675310",
+		Body:      "Hello!",
+		State:     OutboxSending,
+		Attempts:  1,
+		CreatedAt: time.Date(2019, 6, 1, 11, 59, 59, 0, time.UTC),
+	})
+
+	_, err := outbox.Dispatch()
+	assert.NoError(t, err)
+
+	stored, _ := store.Get("1")
+	assert.Equal(t, OutboxSent, stored.State)
+	assert.Equal(t, "SM1", stored.Response.SID)
+	assert.Equal(t, 1, m.posts)
+}
@@ -0,0 +1,199 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jeremybower/go-twilio/twiml"
+)
+
+// IncomingMedia is media attached to an incoming MMS message.
+type IncomingMedia struct {
+	URL         string
+	ContentType string
+}
+
+// IncomingMessage is a message received by one of the account's phone
+// numbers, as sent by Twilio to a messaging webhook.
+type IncomingMessage struct {
+	MessageSID          string
+	AccountSID          string
+	MessagingServiceSID string
+	From                string
+	To                  string
+	Body                string
+	NumSegments         int
+	Media               []IncomingMedia
+
+	FromCity    string
+	FromState   string
+	FromZip     string
+	FromCountry string
+	ToCity      string
+	ToState     string
+	ToZip       string
+	ToCountry   string
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// maxIncomingMedia is the most media Twilio sends with a message.
+const maxIncomingMedia = 10
+
+// ParseIncomingMessage parses the parameters of a messaging webhook.
+func ParseIncomingMessage(form url.Values) (*IncomingMessage, error) {
+	msg := &IncomingMessage{
+		MessageSID:          form.Get("MessageSid"),
+		AccountSID:          form.Get("AccountSid"),
+		MessagingServiceSID: form.Get("MessagingServiceSid"),
+		From:                form.Get("From"),
+		To:                  form.Get("To"),
+		Body:                form.Get("Body"),
+		FromCity:            form.Get("FromCity"),
+		FromState:           form.Get("FromState"),
+		FromZip:             form.Get("FromZip"),
+		FromCountry:         form.Get("FromCountry"),
+		ToCity:              form.Get("ToCity"),
+		ToState:             form.Get("ToState"),
+		ToZip:               form.Get("ToZip"),
+		ToCountry:           form.Get("ToCountry"),
+		Form:                form,
+	}
+
+	if msg.MessageSID == "" {
+		msg.MessageSID = form.Get("SmsMessageSid")
+	}
+
+	if msg.MessageSID == "" {
+		return nil, fmt.Errorf("Missing parameter: MessageSid")
+	}
+
+	var err error
+	msg.NumSegments, err = parseIntParam(form, "NumSegments")
+	if err != nil {
+		return nil, err
+	}
+
+	numMedia, err := parseIntParam(form, "NumMedia")
+	if err != nil {
+		return nil, err
+	}
+
+	if numMedia < 0 || numMedia > maxIncomingMedia {
+		return nil, fmt.Errorf("Invalid parameter: NumMedia")
+	}
+
+	for i := 0; i < numMedia; i++ {
+		n := strconv.Itoa(i)
+		msg.Media = append(msg.Media, IncomingMedia{
+			URL:         form.Get("MediaUrl" + n),
+			ContentType: form.Get("MediaContentType" + n),
+		})
+	}
+
+	return msg, nil
+}
+
+// IncomingMessageHandlerFunc handles an incoming message and returns the
+// TwiML reply. A nil reply sends an empty response.
+type IncomingMessageHandlerFunc func(
+	ctx context.Context,
+	msg *IncomingMessage,
+) (*twiml.MessagingResponse, error)
+
+// IncomingMessageHandler is an http.Handler for messaging webhooks. It
+// validates the request's signature, parses the incoming message and writes
+// the TwiML reply returned by its handler function.
+type IncomingMessageHandler struct {
+	validator *RequestValidator
+	handler   IncomingMessageHandlerFunc
+}
+
+// NewIncomingMessageHandler will create a handler that validates requests
+// with the validator and handles them with the function. Requests are not
+// validated when the validator is nil.
+func NewIncomingMessageHandler(
+	validator *RequestValidator,
+	handler IncomingMessageHandlerFunc,
+) *IncomingMessageHandler {
+	return &IncomingMessageHandler{
+		validator: validator,
+		handler:   handler,
+	}
+}
+
+func (h *IncomingMessageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	msg, err := ParseIncomingMessage(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.handler(r.Context(), msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if resp == nil {
+		resp = twiml.NewMessagingResponse()
+	}
+
+	b, err := resp.Marshal()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", twiml.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// validateWebhook validates and parses a webhook request, writing an error
+// response and returning false when it is not valid.
+func validateWebhook(
+	validator *RequestValidator,
+	w http.ResponseWriter,
+	r *http.Request,
+) (url.Values, bool) {
+	if validator != nil {
+		err := validator.ValidateRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return nil, false
+		}
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return r.Form, true
+}
+
+func parseIntParam(form url.Values, key string) (int, error) {
+	value := form.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid parameter: %s", key)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,130 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jeremybower/go-twilio/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedRequest(authToken string, requestURL string, form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, requestURL, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(SignatureHeader, ComputeSignature(authToken, requestURL, form))
+	return r
+}
+
+func TestParseIncomingMessage(t *testing.T) {
+	msg, err := ParseIncomingMessage(url.Values{
+		"MessageSid":        {"MM1"},
+		"AccountSid":        {"AC1"},
+		"From":              {"+15108675310"},
+		"To":                {"+14155552345"},
+		"Body":              {"Look!"},
+		"NumSegments":       {"1"},
+		"NumMedia":          {"2"},
+		"MediaUrl0":         {"https://api.twilio.com/media/0"},
+		"MediaContentType0": {"image/jpeg"},
+		"MediaUrl1":         {"https://api.twilio.com/media/1"},
+		"MediaContentType1": {"image/png"},
+		"FromCity":          {"OAKLAND"},
+		"FromCountry":       {"US"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "MM1", msg.MessageSID)
+	assert.Equal(t, "+15108675310", msg.From)
+	assert.Equal(t, "Look!", msg.Body)
+	assert.Equal(t, 1, msg.NumSegments)
+	assert.Equal(t, "OAKLAND", msg.FromCity)
+	assert.Equal(t, []IncomingMedia{
+		{URL: "https://api.twilio.com/media/0", ContentType: "image/jpeg"},
+		{URL: "https://api.twilio.com/media/1", ContentType: "image/png"},
+	}, msg.Media)
+
+	_, err = ParseIncomingMessage(url.Values{"MessageSid": {"MM1"}, "NumMedia": {"x"}})
+	assert.Equal(t, "Invalid parameter: NumMedia", err.Error())
+
+	_, err = ParseIncomingMessage(url.Values{"MessageSid": {"MM1"}, "NumMedia": {"-1"}})
+	assert.Equal(t, "Invalid parameter: NumMedia", err.Error())
+
+	_, err = ParseIncomingMessage(url.Values{"MessageSid": {"MM1"}, "NumMedia": {"2000000000"}})
+	assert.Equal(t, "Invalid parameter: NumMedia", err.Error())
+
+	msg, err = ParseIncomingMessage(url.Values{"MessageSid": {"MM1"}, "NumMedia": {"10"}})
+	assert.NoError(t, err)
+	assert.Len(t, msg.Media, 10)
+
+	_, err = ParseIncomingMessage(url.Values{})
+	assert.Equal(t, "Missing parameter: MessageSid", err.Error())
+}
+
+func TestIncomingMessageHandler(t *testing.T) {
+	handler := NewIncomingMessageHandler(
+		NewRequestValidator("token"),
+		func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+			assert.NotNil(t, ctx)
+			assert.Equal(t, "HELP", msg.Body)
+
+			r := twiml.NewMessagingResponse()
+			r.Message("How can we help?")
+			return r, nil
+		})
+
+	form := url.Values{
+		"MessageSid": {"MM1"},
+		"From":       {"+15108675310"},
+		"Body":       {"HELP"},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/sms", form))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, twiml.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Response><Message><Body>How can we help?</Body></Message></Response>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("forged", "http://example.com/sms", form))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestIncomingMessageHandlerErrors(t *testing.T) {
+	handler := NewIncomingMessageHandler(
+		nil,
+		func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+			if msg.Body == "fail" {
+				return nil, errors.New("test error")
+			}
+
+			return nil, nil
+		})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/sms", url.Values{
+		"MessageSid": {"MM1"},
+	}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Response></Response>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/sms", url.Values{
+		"MessageSid": {"MM1"},
+		"Body":       {"fail"},
+	}))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/sms", url.Values{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,637 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremybower/go-twilio/twiml"
+)
+
+// The events that an IVR's action URLs report.
+const (
+	ivrEventInput   = "input"
+	ivrEventTimeout = "timeout"
+)
+
+// IVRNodeFunc renders a node with Go code instead of TwiML generated from the
+// node's fields. It can use the call's URL method to continue the flow.
+type IVRNodeFunc func(ctx context.Context, call *IVRCall) (*twiml.VoiceResponse, error)
+
+// IVRNode is a step of an IVR flow. A node that gathers input plays its
+// prompt, waits for the caller and continues to the node chosen by the
+// input. Other nodes play their prompt and then dial a number, continue to
+// the next node or hang up.
+type IVRNode struct {
+	// Say and Play are the prompt. Voice and Language apply to Say.
+	Say      string      `json:"say,omitempty"`
+	Play     string      `json:"play,omitempty"`
+	Voice    twiml.Voice `json:"voice,omitempty"`
+	Language string      `json:"language,omitempty"`
+
+	// Input is the kind of input to gather. It defaults to DTMF when the
+	// node has options, a pattern or saves its input.
+	Input       twiml.GatherInput `json:"input,omitempty"`
+	NumDigits   int               `json:"num_digits,omitempty"`
+	Timeout     int               `json:"timeout,omitempty"`
+	FinishOnKey string            `json:"finish_on_key,omitempty"`
+
+	// Options maps digits or spoken words to the name of the next node.
+	Options map[string]string `json:"options,omitempty"`
+
+	// Pattern is a regular expression that input, other than the options,
+	// must match in full. Validate, when set, must also return true. Input
+	// is invalid when it matches no option and the node has options but
+	// neither a pattern nor a validate function.
+	Pattern  string                  `json:"pattern,omitempty"`
+	Validate func(input string) bool `json:"-"`
+
+	// Save is the name of the call data that valid input is saved as.
+	Save string `json:"save,omitempty"`
+
+	// Next is the node that follows valid input or the prompt. The call is
+	// hung up when it is empty.
+	Next string `json:"next,omitempty"`
+
+	// Dial is a phone number to connect the call to after the prompt.
+	Dial string `json:"dial,omitempty"`
+
+	// InvalidPrompt and TimeoutPrompt replace the IVR's prompts for this
+	// node, and Fallback replaces its fallback node.
+	InvalidPrompt string `json:"invalid_prompt,omitempty"`
+	TimeoutPrompt string `json:"timeout_prompt,omitempty"`
+	Fallback      string `json:"fallback,omitempty"`
+
+	// Handler, when set, renders the node instead.
+	Handler IVRNodeFunc `json:"-"`
+
+	// pattern is Pattern compiled by Check.
+	pattern *regexp.Regexp
+}
+
+// gathers returns true when the node waits for input.
+func (node *IVRNode) gathers() bool {
+	return node.Input != "" ||
+		len(node.Options) > 0 ||
+		node.Pattern != "" ||
+		node.Validate != nil ||
+		node.Save != ""
+}
+
+// route returns the node that follows the input and whether the input is
+// valid.
+func (node *IVRNode) route(input string) (string, bool, error) {
+	for option, next := range node.Options {
+		if strings.EqualFold(option, input) {
+			return next, true, nil
+		}
+	}
+
+	if input == "" {
+		return "", false, nil
+	}
+
+	if node.Pattern == "" && node.Validate == nil {
+		return node.Next, len(node.Options) == 0, nil
+	}
+
+	if node.Pattern != "" {
+		pattern := node.pattern
+		if pattern == nil {
+			var err error
+			pattern, err = compileIVRPattern(node.Pattern)
+			if err != nil {
+				return "", false, err
+			}
+		}
+
+		if !pattern.MatchString(input) {
+			return "", false, nil
+		}
+	}
+
+	if node.Validate != nil && !node.Validate(input) {
+		return "", false, nil
+	}
+
+	return node.Next, true, nil
+}
+
+// compileIVRPattern compiles a pattern that must match input in full.
+func compileIVRPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// IVRCall is a call's progress through an IVR flow.
+type IVRCall struct {
+	Request *VoiceRequest
+
+	// Node is the name of the current node and Attempt counts the times it
+	// has been prompted, starting at 1.
+	Node    string
+	Attempt int
+
+	// Input is the caller's most recent valid input.
+	Input string
+
+	// Data is the input saved by nodes. Changes are kept when Data is sent
+	// in action URLs or saved in the IVR's store.
+	Data map[string]string
+
+	path       string
+	storedData bool
+}
+
+// URL returns the action URL of the node, which is relative to the IVR's
+// path.
+func (call *IVRCall) URL(node string) string {
+	return call.url(node, 1, "")
+}
+
+func (call *IVRCall) url(node string, attempt int, event string) string {
+	v := url.Values{}
+	v.Set("node", node)
+	if attempt > 1 {
+		v.Set("attempt", strconv.Itoa(attempt))
+	}
+
+	setValue(v, "event", event)
+	if !call.storedData {
+		for key, value := range call.Data {
+			v.Set("data."+key, value)
+		}
+	}
+
+	return call.path + "?" + v.Encode()
+}
+
+// IVRStore keeps the data saved by an IVR's nodes for each call, instead of
+// sending it in action URLs.
+type IVRStore interface {
+	// Get returns the data for the call, or nil when there is none.
+	Get(callSID string) (map[string]string, error)
+
+	// Put replaces the data for the call.
+	Put(callSID string, data map[string]string) error
+}
+
+// MemoryIVRStore keeps IVR data in memory. Data is forgotten once it has not
+// been changed for the TTL.
+type MemoryIVRStore struct {
+	// TTL is how long to keep data after it was last changed.
+	TTL time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*memoryIVRStoreEntry
+}
+
+type memoryIVRStoreEntry struct {
+	data      map[string]string
+	expiresAt time.Time
+}
+
+// NewMemoryIVRStore will create an empty in-memory store that keeps data for
+// an hour.
+func NewMemoryIVRStore() *MemoryIVRStore {
+	return &MemoryIVRStore{
+		TTL:     time.Hour,
+		Now:     time.Now,
+		entries: map[string]*memoryIVRStoreEntry{},
+	}
+}
+
+// Get returns the data for the call, or nil when there is none.
+func (store *MemoryIVRStore) Get(callSID string) (map[string]string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry, ok := store.entries[callSID]
+	if !ok || !store.Now().Before(entry.expiresAt) {
+		return nil, nil
+	}
+
+	return copyIVRData(entry.data), nil
+}
+
+// Put replaces the data for the call.
+func (store *MemoryIVRStore) Put(callSID string, data map[string]string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.Now()
+	for key, entry := range store.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(store.entries, key)
+		}
+	}
+
+	store.entries[callSID] = &memoryIVRStoreEntry{
+		data:      copyIVRData(data),
+		expiresAt: now.Add(store.TTL),
+	}
+
+	return nil
+}
+
+func copyIVRData(data map[string]string) map[string]string {
+	copied := map[string]string{}
+	for key, value := range data {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+// IVR is an http.Handler for a declarative IVR flow. Each response is
+// generated from the call's current node, and Gather and Redirect verbs call
+// back to the same path with the node in the query string.
+//
+// When a caller enters invalid input or does not respond, the node is
+// prompted again after the invalid or timeout prompt. After the maximum
+// number of attempts, the call continues to the fallback node, or says
+// goodbye and hangs up when there is none.
+type IVR struct {
+	validator *RequestValidator
+
+	Start string              `json:"start"`
+	Nodes map[string]*IVRNode `json:"nodes"`
+
+	// MaxAttempts is the number of times a node is prompted before the call
+	// continues to the fallback node.
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	Fallback    string `json:"fallback,omitempty"`
+
+	InvalidPrompt string `json:"invalid_prompt,omitempty"`
+	TimeoutPrompt string `json:"timeout_prompt,omitempty"`
+	GoodbyePrompt string `json:"goodbye_prompt,omitempty"`
+
+	// Store, when set, keeps the data saved by nodes. Otherwise the data is
+	// sent in action URLs.
+	Store IVRStore `json:"-"`
+}
+
+// NewIVR will create an IVR that starts at the named node and validates
+// requests with the validator. Requests are not validated when the validator
+// is nil.
+func NewIVR(validator *RequestValidator, start string) *IVR {
+	ivr := &IVR{Start: start}
+	ivr.init(validator)
+	return ivr
+}
+
+// ParseIVR will create an IVR from its JSON definition and check it. Nodes
+// that need Go code, such as handlers, can be added to it afterwards.
+func ParseIVR(validator *RequestValidator, data []byte) (*IVR, error) {
+	ivr := &IVR{}
+	err := json.Unmarshal(data, ivr)
+	if err != nil {
+		return nil, err
+	}
+
+	ivr.init(validator)
+	err = ivr.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return ivr, nil
+}
+
+func (ivr *IVR) init(validator *RequestValidator) {
+	ivr.validator = validator
+	if ivr.Nodes == nil {
+		ivr.Nodes = map[string]*IVRNode{}
+	}
+
+	if ivr.MaxAttempts == 0 {
+		ivr.MaxAttempts = 3
+	}
+
+	if ivr.InvalidPrompt == "" {
+		ivr.InvalidPrompt = "Sorry, that is not a valid choice."
+	}
+
+	if ivr.TimeoutPrompt == "" {
+		ivr.TimeoutPrompt = "Sorry, I didn't get your response."
+	}
+
+	if ivr.GoodbyePrompt == "" {
+		ivr.GoodbyePrompt = "Goodbye."
+	}
+}
+
+// Node adds or replaces a node.
+func (ivr *IVR) Node(name string, node *IVRNode) *IVR {
+	ivr.Nodes[name] = node
+	return ivr
+}
+
+// Check returns an error when a node that is referred to does not exist, a
+// pattern is not a valid regular expression or a node both dials and
+// continues to another node. It compiles the patterns, so it should be
+// called again after nodes are changed.
+func (ivr *IVR) Check() error {
+	exists := func(name string) error {
+		if _, ok := ivr.Nodes[name]; !ok {
+			return fmt.Errorf("Unknown IVR node: %s", name)
+		}
+
+		return nil
+	}
+
+	err := exists(ivr.Start)
+	if err != nil {
+		return err
+	}
+
+	if ivr.Fallback != "" {
+		err = exists(ivr.Fallback)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, node := range ivr.Nodes {
+		refs := []string{node.Next, node.Fallback}
+		for _, next := range node.Options {
+			refs = append(refs, next)
+		}
+
+		for _, ref := range refs {
+			if ref == "" {
+				continue
+			}
+
+			err = exists(ref)
+			if err != nil {
+				return err
+			}
+		}
+
+		if node.Dial != "" && node.Next != "" {
+			return fmt.Errorf("IVR node %s cannot have both dial and next", name)
+		}
+
+		node.pattern = nil
+		if node.Pattern != "" {
+			node.pattern, err = compileIVRPattern(node.Pattern)
+			if err != nil {
+				return fmt.Errorf("Invalid IVR pattern for %s: %s", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ivr *IVR) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(ivr.validator, w, r)
+	if !ok {
+		return
+	}
+
+	req, err := ParseVoiceRequest(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	call, err := ivr.call(req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := ivr.step(r.Context(), call, r.URL.Query().Get("event"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeVoiceResponse(w, resp)
+}
+
+// call restores a call's progress from the query string and the store.
+func (ivr *IVR) call(req *VoiceRequest, r *http.Request) (*IVRCall, error) {
+	query := r.URL.Query()
+	call := &IVRCall{
+		Request:    req,
+		Node:       query.Get("node"),
+		Data:       map[string]string{},
+		path:       r.URL.Path,
+		storedData: ivr.Store != nil,
+	}
+
+	if call.Node == "" {
+		call.Node = ivr.Start
+	}
+
+	call.Attempt, _ = strconv.Atoi(query.Get("attempt"))
+	if call.Attempt < 1 {
+		call.Attempt = 1
+	}
+
+	if ivr.Store != nil {
+		data, err := ivr.Store.Get(req.CallSID)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range data {
+			call.Data[key] = value
+		}
+	} else {
+		for key := range query {
+			if strings.HasPrefix(key, "data.") {
+				call.Data[strings.TrimPrefix(key, "data.")] = query.Get(key)
+			}
+		}
+	}
+
+	return call, nil
+}
+
+// step handles the caller's input or timeout for the current node, or
+// renders the node when the call has just arrived at it.
+func (ivr *IVR) step(ctx context.Context, call *IVRCall, event string) (*twiml.VoiceResponse, error) {
+	node, err := ivr.node(call.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt string
+	switch event {
+	case ivrEventInput:
+		input := call.Request.Digits
+		if input == "" {
+			input = strings.TrimRight(strings.TrimSpace(call.Request.SpeechResult), ".!?")
+		}
+
+		next, ok, err := node.route(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return ivr.accept(ctx, call, node, input, next)
+		}
+
+		prompt = firstNonEmpty(node.InvalidPrompt, ivr.InvalidPrompt)
+
+	case ivrEventTimeout:
+		prompt = firstNonEmpty(node.TimeoutPrompt, ivr.TimeoutPrompt)
+
+	default:
+		return ivr.render(ctx, call, call.Node, call.Attempt, "")
+	}
+
+	if call.Attempt >= ivr.MaxAttempts {
+		fallback := firstNonEmpty(node.Fallback, ivr.Fallback)
+		if fallback == "" {
+			resp := twiml.NewVoiceResponse()
+			resp.Say(ivr.GoodbyePrompt)
+			resp.Hangup()
+			return resp, nil
+		}
+
+		return ivr.render(ctx, call, fallback, 1, "")
+	}
+
+	return ivr.render(ctx, call, call.Node, call.Attempt+1, prompt)
+}
+
+// accept saves valid input and continues to the next node.
+func (ivr *IVR) accept(
+	ctx context.Context,
+	call *IVRCall,
+	node *IVRNode,
+	input string,
+	next string,
+) (*twiml.VoiceResponse, error) {
+	call.Input = input
+	if node.Save != "" {
+		call.Data[node.Save] = input
+		if ivr.Store != nil {
+			err := ivr.Store.Put(call.Request.CallSID, call.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if next == "" {
+		resp := twiml.NewVoiceResponse()
+		resp.Hangup()
+		return resp, nil
+	}
+
+	return ivr.render(ctx, call, next, 1, "")
+}
+
+// render generates the TwiML for a node, starting with the prompt when it is
+// not empty.
+func (ivr *IVR) render(
+	ctx context.Context,
+	call *IVRCall,
+	name string,
+	attempt int,
+	prompt string,
+) (*twiml.VoiceResponse, error) {
+	node, err := ivr.node(name)
+	if err != nil {
+		return nil, err
+	}
+
+	call.Node = name
+	call.Attempt = attempt
+	if node.Handler != nil {
+		return node.Handler(ctx, call)
+	}
+
+	resp := twiml.NewVoiceResponse()
+	if prompt != "" {
+		say := resp.Say(prompt)
+		say.Voice = node.Voice
+		say.Language = node.Language
+	}
+
+	if node.gathers() {
+		gather := resp.Gather()
+		gather.Action = call.url(name, attempt, ivrEventInput)
+		gather.Input = node.Input
+		gather.NumDigits = node.NumDigits
+		gather.Language = node.Language
+		if node.Timeout > 0 {
+			timeout := node.Timeout
+			gather.Timeout = &timeout
+		}
+
+		if node.FinishOnKey != "" {
+			finishOnKey := node.FinishOnKey
+			gather.FinishOnKey = &finishOnKey
+		}
+
+		if node.Say != "" {
+			say := gather.Say(node.Say)
+			say.Voice = node.Voice
+			say.Language = node.Language
+		}
+
+		if node.Play != "" {
+			gather.Play(node.Play)
+		}
+
+		resp.Redirect(call.url(name, attempt, ivrEventTimeout))
+		return resp, nil
+	}
+
+	if node.Say != "" {
+		say := resp.Say(node.Say)
+		say.Voice = node.Voice
+		say.Language = node.Language
+	}
+
+	if node.Play != "" {
+		resp.Play(node.Play)
+	}
+
+	switch {
+	case node.Dial != "":
+		resp.Dial(node.Dial)
+	case node.Next != "":
+		resp.Redirect(call.URL(node.Next))
+	default:
+		resp.Hangup()
+	}
+
+	return resp, nil
+}
+
+func (ivr *IVR) node(name string) (*IVRNode, error) {
+	node, ok := ivr.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown IVR node: %s", name)
+	}
+
+	return node, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,176 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jeremybower/go-twilio/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveIVR(t *testing.T, ivr *IVR, target string, form url.Values) string {
+	form.Set("CallSid", "CA1")
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	ivr.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, twiml.ContentType, w.Header().Get("Content-Type"))
+	return strings.TrimPrefix(w.Body.String(), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+}
+
+func newTestIVR() *IVR {
+	return NewIVR(nil, "main").
+		Node("main", &IVRNode{
+			Say:     "Press 1 for sales or 2 for support.",
+			Options: map[string]string{"1": "sales", "2": "support", "support": "support"},
+			Input:   twiml.GatherInputDTMFSpeech,
+		}).
+		Node("sales", &IVRNode{Say: "Connecting you to sales.", Dial: "+15108675310"}).
+		Node("support", &IVRNode{Say: "Please hold.", Next: "main"})
+}
+
+func TestIVRRendersStartNode(t *testing.T) {
+	body := serveIVR(t, newTestIVR(), "/ivr", url.Values{})
+	assert.Equal(t, `<Response>`+
+		`<Gather input="dtmf speech" action="/ivr?event=input&amp;node=main">`+
+		`<Say>Press 1 for sales or 2 for support.</Say>`+
+		`</Gather>`+
+		`<Redirect>/ivr?event=timeout&amp;node=main</Redirect>`+
+		`</Response>`, body)
+}
+
+func TestIVRRoutesInput(t *testing.T) {
+	ivr := newTestIVR()
+
+	body := serveIVR(t, ivr, "/ivr?event=input&node=main", url.Values{"Digits": {"1"}})
+	assert.Equal(t, `<Response>`+
+		`<Say>Connecting you to sales.</Say>`+
+		`<Dial>+15108675310</Dial>`+
+		`</Response>`, body)
+
+	body = serveIVR(t, ivr, "/ivr?event=input&node=main", url.Values{"SpeechResult": {"Support."}})
+	assert.Equal(t, `<Response>`+
+		`<Say>Please hold.</Say>`+
+		`<Redirect>/ivr?node=main</Redirect>`+
+		`</Response>`, body)
+}
+
+func TestIVRRetriesInvalidInputAndTimeouts(t *testing.T) {
+	ivr := newTestIVR()
+
+	body := serveIVR(t, ivr, "/ivr?event=input&node=main", url.Values{"Digits": {"9"}})
+	assert.Contains(t, body, `<Response><Say>Sorry, that is not a valid choice.</Say>`)
+	assert.Contains(t, body, `action="/ivr?attempt=2&amp;event=input&amp;node=main"`)
+
+	body = serveIVR(t, ivr, "/ivr?attempt=2&event=timeout&node=main", url.Values{})
+	assert.Contains(t, body, `<Response><Say>Sorry, I didn&#39;t get your response.</Say>`)
+	assert.Contains(t, body, `action="/ivr?attempt=3&amp;event=input&amp;node=main"`)
+
+	body = serveIVR(t, ivr, "/ivr?attempt=3&event=timeout&node=main", url.Values{})
+	assert.Equal(t, `<Response><Say>Goodbye.</Say><Hangup></Hangup></Response>`, body)
+
+	ivr.Fallback = "sales"
+	body = serveIVR(t, ivr, "/ivr?attempt=3&event=timeout&node=main", url.Values{})
+	assert.Contains(t, body, `<Dial>+15108675310</Dial>`)
+}
+
+func TestIVRSavesDataInQuery(t *testing.T) {
+	ivr := NewIVR(nil, "account").
+		Node("account", &IVRNode{
+			Say:         "Enter your account number followed by pound.",
+			Pattern:     `[0-9]{6}`,
+			FinishOnKey: "#",
+			Save:        "account",
+			Next:        "confirm",
+		}).
+		Node("confirm", &IVRNode{
+			Handler: func(ctx context.Context, call *IVRCall) (*twiml.VoiceResponse, error) {
+				resp := twiml.NewVoiceResponse()
+				resp.Say("Account " + call.Data["account"])
+				resp.Redirect(call.URL("account"))
+				return resp, nil
+			},
+		})
+
+	body := serveIVR(t, ivr, "/ivr?event=input&node=account", url.Values{"Digits": {"12345"}})
+	assert.Contains(t, body, `<Say>Sorry, that is not a valid choice.</Say>`)
+
+	body = serveIVR(t, ivr, "/ivr?event=input&node=account", url.Values{"Digits": {"123456"}})
+	assert.Equal(t, `<Response>`+
+		`<Say>Account 123456</Say>`+
+		`<Redirect>/ivr?data.account=123456&amp;node=account</Redirect>`+
+		`</Response>`, body)
+}
+
+func TestIVRSavesDataInStore(t *testing.T) {
+	store := NewMemoryIVRStore()
+	ivr := NewIVR(nil, "pin").
+		Node("pin", &IVRNode{NumDigits: 4, Save: "pin", Next: "done"}).
+		Node("done", &IVRNode{
+			Handler: func(ctx context.Context, call *IVRCall) (*twiml.VoiceResponse, error) {
+				resp := twiml.NewVoiceResponse()
+				resp.Redirect(call.URL("pin"))
+				return resp, nil
+			},
+		})
+	ivr.Store = store
+
+	body := serveIVR(t, ivr, "/ivr?event=input&node=pin", url.Values{"Digits": {"1234"}})
+	assert.Equal(t, `<Response><Redirect>/ivr?node=pin</Redirect></Response>`, body)
+
+	data, err := store.Get("CA1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pin": "1234"}, data)
+}
+
+func TestParseIVR(t *testing.T) {
+	ivr, err := ParseIVR(nil, []byte(`{
+		"start": "main",
+		"max_attempts": 2,
+		"nodes": {
+			"main": {"say": "Press 1.", "options": {"1": "bye"}, "timeout": 5},
+			"bye": {"say": "Goodbye."}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ivr.MaxAttempts)
+
+	body := serveIVR(t, ivr, "/ivr", url.Values{})
+	assert.Contains(t, body, `<Gather action="/ivr?event=input&amp;node=main" timeout="5">`)
+
+	body = serveIVR(t, ivr, "/ivr?event=input&node=main", url.Values{"Digits": {"1"}})
+	assert.Equal(t, `<Response><Say>Goodbye.</Say><Hangup></Hangup></Response>`, body)
+
+	_, err = ParseIVR(nil, []byte(`{"start": "main", "nodes": {"main": {"next": "missing"}}}`))
+	assert.EqualError(t, err, "Unknown IVR node: missing")
+
+	_, err = ParseIVR(nil, []byte(`{"start": "main", "nodes": {"main": {"pattern": "("}}}`))
+	assert.Error(t, err)
+
+	_, err = ParseIVR(nil, []byte(`{"start": "main", "nodes": {"main": {"dial": "+15108675310", "next": "main"}}}`))
+	assert.EqualError(t, err, "IVR node main cannot have both dial and next")
+
+	ivr, err = ParseIVR(nil, []byte(`{
+		"start": "main",
+		"nodes": {"main": {"say": "Enter your PIN.", "pattern": "[0-9]{4}", "next": "main"}}
+	}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, ivr.Nodes["main"].pattern)
+
+	next, ok, err := ivr.Nodes["main"].route("1234")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "main", next)
+
+	_, ok, err = ivr.Nodes["main"].route("12345")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,263 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// MediaStreamEventType is the type of a Media Streams message.
+type MediaStreamEventType string
+
+// The types of Media Streams messages. Twilio sends every type except
+// clear, and accepts media, mark and clear on bidirectional streams.
+const (
+	MediaStreamConnected MediaStreamEventType = "connected"
+	MediaStreamStart     MediaStreamEventType = "start"
+	MediaStreamMedia     MediaStreamEventType = "media"
+	MediaStreamMark      MediaStreamEventType = "mark"
+	MediaStreamStop      MediaStreamEventType = "stop"
+	MediaStreamDTMF      MediaStreamEventType = "dtmf"
+	MediaStreamClear     MediaStreamEventType = "clear"
+)
+
+// MediaStreamEvent is a message sent over a Media Streams WebSocket. Only
+// the field for the event's type is set.
+type MediaStreamEvent struct {
+	Event          MediaStreamEventType `json:"event"`
+	SequenceNumber string               `json:"sequenceNumber,omitempty"`
+	StreamSID      string               `json:"streamSid,omitempty"`
+
+	// Protocol and Version are set for connected events.
+	Protocol string `json:"protocol,omitempty"`
+	Version  string `json:"version,omitempty"`
+
+	Start *MediaStreamStartInfo `json:"start,omitempty"`
+	Media *MediaStreamMediaInfo `json:"media,omitempty"`
+	Mark  *MediaStreamMarkInfo  `json:"mark,omitempty"`
+	Stop  *MediaStreamStopInfo  `json:"stop,omitempty"`
+	DTMF  *MediaStreamDTMFInfo  `json:"dtmf,omitempty"`
+}
+
+// MediaStreamStartInfo describes a stream when it starts.
+type MediaStreamStartInfo struct {
+	StreamSID        string            `json:"streamSid"`
+	AccountSID       string            `json:"accountSid"`
+	CallSID          string            `json:"callSid"`
+	Tracks           []string          `json:"tracks"`
+	CustomParameters map[string]string `json:"customParameters,omitempty"`
+	MediaFormat      MediaFormat       `json:"mediaFormat"`
+}
+
+// MediaFormat is the format of a stream's audio. Twilio streams 8 kHz mono
+// μ-law, which is audio/x-mulaw.
+type MediaFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+}
+
+// MediaStreamMediaInfo is a chunk of audio. Payload is the raw audio, which
+// is base64 encoded in the message.
+type MediaStreamMediaInfo struct {
+	Track     string `json:"track,omitempty"`
+	Chunk     string `json:"chunk,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Payload   []byte `json:"payload"`
+}
+
+// MediaStreamMarkInfo names a point in the audio sent to Twilio. Twilio
+// sends the mark back once the audio before it has been played.
+type MediaStreamMarkInfo struct {
+	Name string `json:"name"`
+}
+
+// MediaStreamStopInfo describes a stream when it stops.
+type MediaStreamStopInfo struct {
+	AccountSID string `json:"accountSid"`
+	CallSID    string `json:"callSid"`
+}
+
+// MediaStreamDTMFInfo is a digit pressed by the caller on a bidirectional
+// stream.
+type MediaStreamDTMFInfo struct {
+	Track string `json:"track"`
+	Digit string `json:"digit"`
+}
+
+// MediaStream is one side of a Media Streams WebSocket connection. Read
+// must not be called concurrently, but messages can be sent while reading.
+type MediaStream struct {
+	conn *wsConn
+
+	mu        sync.Mutex
+	streamSID string
+	start     *MediaStreamStartInfo
+	sequence  int
+}
+
+// DialMediaStream opens a Media Streams connection to a ws or wss URL, as
+// Twilio does. It is useful for testing stream handlers with a local
+// server.
+func DialMediaStream(rawurl string, header http.Header) (*MediaStream, error) {
+	conn, err := dialWebSocket(rawurl, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MediaStream{conn: conn}, nil
+}
+
+// Read returns the next message. It returns io.EOF once the connection has
+// been closed.
+func (s *MediaStream) Read() (*MediaStreamEvent, error) {
+	for {
+		opcode, b, err := s.conn.readMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		if opcode != wsText {
+			continue
+		}
+
+		event := &MediaStreamEvent{}
+		err = json.Unmarshal(b, event)
+		if err != nil {
+			return nil, err
+		}
+
+		if event.Start != nil {
+			s.mu.Lock()
+			s.streamSID = event.StreamSID
+			if s.streamSID == "" {
+				s.streamSID = event.Start.StreamSID
+			}
+			s.start = event.Start
+			s.mu.Unlock()
+		}
+
+		return event, nil
+	}
+}
+
+// StreamSID returns the SID of the stream once it has started.
+func (s *MediaStream) StreamSID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.streamSID
+}
+
+// Start returns the description of the stream once it has started.
+func (s *MediaStream) Start() *MediaStreamStartInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.start
+}
+
+// Send sends a message. The stream SID is filled in when it is empty. On a
+// dialed stream, which acts as Twilio, messages are numbered when they do
+// not have a sequence number.
+func (s *MediaStream) Send(event *MediaStreamEvent) error {
+	s.mu.Lock()
+	copied := *event
+	if copied.StreamSID == "" {
+		copied.StreamSID = s.streamSID
+	}
+
+	if copied.SequenceNumber == "" && s.conn.client {
+		s.sequence++
+		copied.SequenceNumber = strconv.Itoa(s.sequence)
+	}
+	s.mu.Unlock()
+
+	b, err := json.Marshal(&copied)
+	if err != nil {
+		return err
+	}
+
+	return s.conn.writeFrame(wsText, b)
+}
+
+// SendMedia sends μ-law audio to be played to the caller on a bidirectional
+// stream.
+func (s *MediaStream) SendMedia(payload []byte) error {
+	return s.Send(&MediaStreamEvent{
+		Event: MediaStreamMedia,
+		Media: &MediaStreamMediaInfo{Payload: payload},
+	})
+}
+
+// SendMark sends a mark that Twilio sends back once the audio sent before it
+// has been played.
+func (s *MediaStream) SendMark(name string) error {
+	return s.Send(&MediaStreamEvent{
+		Event: MediaStreamMark,
+		Mark:  &MediaStreamMarkInfo{Name: name},
+	})
+}
+
+// Clear discards the audio that has been sent but not yet played. Twilio
+// sends back the marks that were discarded.
+func (s *MediaStream) Clear() error {
+	return s.Send(&MediaStreamEvent{Event: MediaStreamClear})
+}
+
+// Close closes the connection.
+func (s *MediaStream) Close() error {
+	return s.conn.close()
+}
+
+// MediaStreamHandlerFunc handles a Media Streams connection until the stream
+// stops. The connection is closed when it returns.
+type MediaStreamHandlerFunc func(ctx context.Context, stream *MediaStream) error
+
+// MediaStreamHandler is an http.Handler for Media Streams WebSocket
+// connections, such as those opened by a Stream in a Connect or Start verb.
+type MediaStreamHandler struct {
+	validator *RequestValidator
+	handler   MediaStreamHandlerFunc
+
+	// OnError is called when a handler returns an error other than the
+	// stream closing.
+	OnError func(error)
+}
+
+// NewMediaStreamHandler will create a handler that validates requests with
+// the validator and handles each connection with the function. Twilio signs
+// the wss URL of the stream, so the validator's BaseURL should use the wss
+// scheme. Requests are not validated when the validator is nil.
+func NewMediaStreamHandler(
+	validator *RequestValidator,
+	handler MediaStreamHandlerFunc,
+) *MediaStreamHandler {
+	return &MediaStreamHandler{
+		validator: validator,
+		handler:   handler,
+	}
+}
+
+func (h *MediaStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+
+	stream := &MediaStream{conn: conn}
+	defer stream.Close()
+
+	err = h.handler(r.Context(), stream)
+	if err != nil && err != io.EOF && h.OnError != nil {
+		h.OnError(err)
+	}
+}
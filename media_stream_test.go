@@ -0,0 +1,163 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dialTestMediaStream(t *testing.T, server *httptest.Server) *MediaStream {
+	stream, err := DialMediaStream("ws"+strings.TrimPrefix(server.URL, "http")+"/stream", nil)
+	assert.NoError(t, err)
+	return stream
+}
+
+func TestMediaStreamHandler(t *testing.T) {
+	var start *MediaStreamStartInfo
+	var digits []string
+	handler := NewMediaStreamHandler(nil, func(ctx context.Context, stream *MediaStream) error {
+		for {
+			event, err := stream.Read()
+			if err != nil {
+				return err
+			}
+
+			switch event.Event {
+			case MediaStreamStart:
+				start = stream.Start()
+			case MediaStreamMedia:
+				err = stream.SendMedia(event.Media.Payload)
+				if err == nil {
+					err = stream.SendMark("echo")
+				}
+			case MediaStreamDTMF:
+				digits = append(digits, event.DTMF.Digit)
+				err = stream.Clear()
+			case MediaStreamStop:
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	twilio := dialTestMediaStream(t, server)
+	defer twilio.Close()
+
+	assert.NoError(t, twilio.Send(&MediaStreamEvent{Event: MediaStreamConnected, Protocol: "Call", Version: "1.0.0"}))
+	assert.NoError(t, twilio.Send(&MediaStreamEvent{
+		Event:     MediaStreamStart,
+		StreamSID: "MZ1",
+		Start: &MediaStreamStartInfo{
+			StreamSID:        "MZ1",
+			CallSID:          "CA1",
+			Tracks:           []string{"inbound"},
+			CustomParameters: map[string]string{"customer": "42"},
+			MediaFormat:      MediaFormat{Encoding: "audio/x-mulaw", SampleRate: 8000, Channels: 1},
+		},
+	}))
+
+	audio := []byte{0xff, 0x7f, 0x00, 0x80}
+	assert.NoError(t, twilio.Send(&MediaStreamEvent{
+		Event:     MediaStreamMedia,
+		StreamSID: "MZ1",
+		Media:     &MediaStreamMediaInfo{Track: "inbound", Chunk: "1", Timestamp: "20", Payload: audio},
+	}))
+
+	event, err := twilio.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, &MediaStreamEvent{
+		Event:     MediaStreamMedia,
+		StreamSID: "MZ1",
+		Media:     &MediaStreamMediaInfo{Payload: audio},
+	}, event)
+
+	event, err = twilio.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", event.Mark.Name)
+
+	assert.NoError(t, twilio.Send(&MediaStreamEvent{
+		Event:     MediaStreamDTMF,
+		StreamSID: "MZ1",
+		DTMF:      &MediaStreamDTMFInfo{Track: "inbound_track", Digit: "5"},
+	}))
+
+	event, err = twilio.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, &MediaStreamEvent{Event: MediaStreamClear, StreamSID: "MZ1"}, event)
+
+	assert.NoError(t, twilio.Send(&MediaStreamEvent{
+		Event:     MediaStreamStop,
+		StreamSID: "MZ1",
+		Stop:      &MediaStreamStopInfo{CallSID: "CA1"},
+	}))
+
+	_, err = twilio.Read()
+	assert.Equal(t, io.EOF, err)
+
+	assert.Equal(t, "CA1", start.CallSID)
+	assert.Equal(t, "42", start.CustomParameters["customer"])
+	assert.Equal(t, []string{"5"}, digits)
+}
+
+func TestMediaStreamLargeMessagesAndPings(t *testing.T) {
+	handler := NewMediaStreamHandler(nil, func(ctx context.Context, stream *MediaStream) error {
+		for {
+			event, err := stream.Read()
+			if err != nil {
+				return err
+			}
+
+			err = stream.SendMedia(event.Media.Payload)
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	twilio := dialTestMediaStream(t, server)
+	defer twilio.Close()
+
+	assert.NoError(t, twilio.conn.writeFrame(wsPing, []byte("ping")))
+	for _, size := range []int{100, 1000, 70000} {
+		audio := make([]byte, size)
+		for i := range audio {
+			audio[i] = byte(i)
+		}
+
+		assert.NoError(t, twilio.SendMedia(audio))
+		event, err := twilio.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, audio, event.Media.Payload)
+	}
+}
+
+func TestMediaStreamHandlerRejectsInvalidRequests(t *testing.T) {
+	handler := NewMediaStreamHandler(nil, func(ctx context.Context, stream *MediaStream) error {
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	handler = NewMediaStreamHandler(NewRequestValidator("token"), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,306 @@
+package twilio
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jeremybower/go-twilio/twiml"
+)
+
+// The default compliance keywords. Twilio's own opt-out handling recognizes
+// the same keywords.
+var (
+	DefaultOptOutKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT"}
+	DefaultOptInKeywords  = []string{"START", "YES", "UNSTOP"}
+	DefaultHelpKeywords   = []string{"HELP", "INFO"}
+)
+
+// ComplianceKeyword is the kind of compliance keyword in a message.
+type ComplianceKeyword string
+
+// The kinds of compliance keywords.
+const (
+	ComplianceOptOut ComplianceKeyword = "opt_out"
+	ComplianceOptIn  ComplianceKeyword = "opt_in"
+	ComplianceHelp   ComplianceKeyword = "help"
+)
+
+// IncomingMessageMiddleware wraps a handler function, such as to log or
+// authorize incoming messages.
+type IncomingMessageMiddleware func(next IncomingMessageHandlerFunc) IncomingMessageHandlerFunc
+
+// MessageRouteMatch describes how an incoming message matched a route.
+type MessageRouteMatch struct {
+	// Keyword is the keyword or prefix that matched, in upper case.
+	Keyword string
+
+	// Args are the words following a prefix, or the groups captured by a
+	// pattern.
+	Args []string
+
+	// Named are the named groups captured by a pattern.
+	Named map[string]string
+
+	// Compliance is set when the message is a compliance keyword. The match
+	// is added to the context before middleware runs, so that middleware can
+	// let compliance keywords through.
+	Compliance ComplianceKeyword
+}
+
+type messageRouteMatchKey struct{}
+
+// MessageRouteMatchFromContext returns the match for the route that is
+// handling the message, or nil when the default handler is handling it.
+func MessageRouteMatchFromContext(ctx context.Context) *MessageRouteMatch {
+	match, _ := ctx.Value(messageRouteMatchKey{}).(*MessageRouteMatch)
+	return match
+}
+
+type messageRoute struct {
+	match   func(body string) *MessageRouteMatch
+	handler IncomingMessageHandlerFunc
+}
+
+// MessageRouter dispatches incoming messages to handlers by keyword, prefix
+// or pattern. Compliance keywords are always handled first, and routes for a
+// destination number are tried before the router's own routes. Pass its
+// Handle method to NewIncomingMessageHandler.
+type MessageRouter struct {
+	// OptOutKeywords, OptInKeywords and HelpKeywords are the compliance
+	// keywords, matched case-insensitively against the whole message.
+	OptOutKeywords []string
+	OptInKeywords  []string
+	HelpKeywords   []string
+
+	// OnOptOut, OnOptIn and OnHelp handle the compliance keywords. A nil
+	// handler sends an empty response, leaving the reply to Twilio's opt-out
+	// handling. Messages with compliance keywords never reach other routes.
+	OnOptOut IncomingMessageHandlerFunc
+	OnOptIn  IncomingMessageHandlerFunc
+	OnHelp   IncomingMessageHandlerFunc
+
+	mu             sync.RWMutex
+	routes         []*messageRoute
+	middleware     []IncomingMessageMiddleware
+	defaultHandler IncomingMessageHandlerFunc
+	numbers        map[string]*MessageRouter
+}
+
+// NewMessageRouter will create a router with the default compliance
+// keywords.
+func NewMessageRouter() *MessageRouter {
+	return &MessageRouter{
+		OptOutKeywords: DefaultOptOutKeywords,
+		OptInKeywords:  DefaultOptInKeywords,
+		HelpKeywords:   DefaultHelpKeywords,
+		numbers:        map[string]*MessageRouter{},
+	}
+}
+
+// Keyword routes messages that consist of only the keyword, ignoring case and
+// surrounding whitespace.
+func (router *MessageRouter) Keyword(keyword string, handler IncomingMessageHandlerFunc) {
+	keyword = strings.ToUpper(keyword)
+	router.add(func(body string) *MessageRouteMatch {
+		if strings.ToUpper(body) != keyword {
+			return nil
+		}
+
+		return &MessageRouteMatch{Keyword: keyword}
+	}, handler)
+}
+
+// Prefix routes messages whose first word is the prefix, ignoring case. The
+// remaining words are the match's arguments, such as 1234 in STATUS 1234.
+func (router *MessageRouter) Prefix(prefix string, handler IncomingMessageHandlerFunc) {
+	prefix = strings.ToUpper(prefix)
+	router.add(func(body string) *MessageRouteMatch {
+		words := strings.Fields(body)
+		if len(words) == 0 || strings.ToUpper(words[0]) != prefix {
+			return nil
+		}
+
+		return &MessageRouteMatch{Keyword: prefix, Args: words[1:]}
+	}, handler)
+}
+
+// Pattern routes messages that match the regular expression. The captured
+// groups are the match's arguments.
+func (router *MessageRouter) Pattern(pattern *regexp.Regexp, handler IncomingMessageHandlerFunc) {
+	router.add(func(body string) *MessageRouteMatch {
+		groups := pattern.FindStringSubmatch(body)
+		if groups == nil {
+			return nil
+		}
+
+		match := &MessageRouteMatch{
+			Args:  groups[1:],
+			Named: map[string]string{},
+		}
+
+		for i, name := range pattern.SubexpNames() {
+			if name != "" {
+				match.Named[name] = groups[i]
+			}
+		}
+
+		return match
+	}, handler)
+}
+
+// Default handles messages that do not match any route.
+func (router *MessageRouter) Default(handler IncomingMessageHandlerFunc) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	router.defaultHandler = handler
+}
+
+// Use adds middleware that wraps every handler, including the compliance
+// handlers. Middleware runs in the order it was added.
+func (router *MessageRouter) Use(middleware ...IncomingMessageMiddleware) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	router.middleware = append(router.middleware, middleware...)
+}
+
+// To returns a router for messages sent to the phone number. Its routes,
+// default handler and middleware apply only to those messages, and are tried
+// before the parent's. Compliance keywords are handled by the parent.
+func (router *MessageRouter) To(number string) *MessageRouter {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	sub, ok := router.numbers[number]
+	if !ok {
+		sub = &MessageRouter{numbers: map[string]*MessageRouter{}}
+		router.numbers[number] = sub
+	}
+
+	return sub
+}
+
+// Handle routes an incoming message. It has the signature of an
+// IncomingMessageHandlerFunc.
+func (router *MessageRouter) Handle(
+	ctx context.Context,
+	msg *IncomingMessage,
+) (*twiml.MessagingResponse, error) {
+	router.mu.RLock()
+	sub := router.numbers[msg.To]
+	router.mu.RUnlock()
+
+	body := strings.TrimSpace(msg.Body)
+	middleware := router.chain()
+	handler, match := router.compliance(body)
+	if match != nil {
+		ctx = context.WithValue(ctx, messageRouteMatchKey{}, match)
+	} else {
+		if sub != nil {
+			if handler = sub.match(body); handler != nil {
+				middleware = append(middleware, sub.chain()...)
+			}
+		}
+
+		if handler == nil {
+			handler = router.match(body)
+		}
+
+		if handler == nil && sub != nil {
+			if handler = sub.defaults(); handler != nil {
+				middleware = append(middleware, sub.chain()...)
+			}
+		}
+
+		if handler == nil {
+			handler = router.defaults()
+		}
+	}
+
+	if handler == nil {
+		handler = emptyMessagingResponse
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler(ctx, msg)
+}
+
+// match returns the handler for the first route that matches the body, with
+// the match added to its context, or nil when no route matches.
+func (router *MessageRouter) match(body string) IncomingMessageHandlerFunc {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+
+	for _, route := range router.routes {
+		match := route.match(body)
+		if match == nil {
+			continue
+		}
+
+		handler := route.handler
+		return func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+			return handler(context.WithValue(ctx, messageRouteMatchKey{}, match), msg)
+		}
+	}
+
+	return nil
+}
+
+func (router *MessageRouter) compliance(body string) (IncomingMessageHandlerFunc, *MessageRouteMatch) {
+	keyword := strings.ToUpper(body)
+	for _, c := range []struct {
+		kind     ComplianceKeyword
+		keywords []string
+		handler  IncomingMessageHandlerFunc
+	}{
+		{ComplianceOptOut, router.OptOutKeywords, router.OnOptOut},
+		{ComplianceOptIn, router.OptInKeywords, router.OnOptIn},
+		{ComplianceHelp, router.HelpKeywords, router.OnHelp},
+	} {
+		for _, k := range c.keywords {
+			if strings.ToUpper(k) != keyword {
+				continue
+			}
+
+			match := &MessageRouteMatch{Keyword: keyword, Compliance: c.kind}
+			if c.handler == nil {
+				return emptyMessagingResponse, match
+			}
+
+			return c.handler, match
+		}
+	}
+
+	return nil, nil
+}
+
+func (router *MessageRouter) chain() []IncomingMessageMiddleware {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+
+	return append([]IncomingMessageMiddleware{}, router.middleware...)
+}
+
+func (router *MessageRouter) defaults() IncomingMessageHandlerFunc {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+
+	return router.defaultHandler
+}
+
+func (router *MessageRouter) add(match func(body string) *MessageRouteMatch, handler IncomingMessageHandlerFunc) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	router.routes = append(router.routes, &messageRoute{match: match, handler: handler})
+}
+
+func emptyMessagingResponse(context.Context, *IncomingMessage) (*twiml.MessagingResponse, error) {
+	return twiml.NewMessagingResponse(), nil
+}
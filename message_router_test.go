@@ -0,0 +1,118 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/jeremybower/go-twilio/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func replyWith(text string) IncomingMessageHandlerFunc {
+	return func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+		r := twiml.NewMessagingResponse()
+		r.Message(text)
+		return r, nil
+	}
+}
+
+func routeReply(t *testing.T, router *MessageRouter, to string, body string) string {
+	resp, err := router.Handle(context.Background(), &IncomingMessage{
+		MessageSID: "MM1",
+		From:       "+15108675310",
+		To:         to,
+		Body:       body,
+	})
+	assert.NoError(t, err)
+
+	if len(resp.Verbs) == 0 {
+		return ""
+	}
+
+	return resp.Verbs[0].(*twiml.Message).Body.Text
+}
+
+func TestMessageRouterRoutes(t *testing.T) {
+	var match *MessageRouteMatch
+	capture := func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+		match = MessageRouteMatchFromContext(ctx)
+		return replyWith("captured")(ctx, msg)
+	}
+
+	router := NewMessageRouter()
+	router.Keyword("join", replyWith("joined"))
+	router.Prefix("STATUS", capture)
+	router.Pattern(regexp.MustCompile(`^(?P<count>\d+) tickets?$`), capture)
+	router.Default(replyWith("default"))
+
+	assert.Equal(t, "joined", routeReply(t, router, "+14155552345", "  Join "))
+	assert.Equal(t, "default", routeReply(t, router, "+14155552345", "join now"))
+
+	assert.Equal(t, "captured", routeReply(t, router, "+14155552345", "status 1234 urgent"))
+	assert.Equal(t, &MessageRouteMatch{Keyword: "STATUS", Args: []string{"1234", "urgent"}}, match)
+
+	assert.Equal(t, "captured", routeReply(t, router, "+14155552345", "2 tickets"))
+	assert.Equal(t, &MessageRouteMatch{
+		Args:  []string{"2"},
+		Named: map[string]string{"count": "2"},
+	}, match)
+
+	assert.Equal(t, "default", routeReply(t, router, "+14155552345", "hello"))
+}
+
+func TestMessageRouterCompliance(t *testing.T) {
+	router := NewMessageRouter()
+	router.Keyword("STOP", replyWith("never reached"))
+	router.To("+14155552345").Keyword("HELP", replyWith("never reached"))
+	router.Default(replyWith("default"))
+	router.OnHelp = replyWith("Reply STOP to unsubscribe.")
+
+	assert.Equal(t, "", routeReply(t, router, "+14155552345", "stop"))
+	assert.Equal(t, "", routeReply(t, router, "+14155552345", "Unsubscribe"))
+	assert.Equal(t, "Reply STOP to unsubscribe.", routeReply(t, router, "+14155552345", "help"))
+	assert.Equal(t, "default", routeReply(t, router, "+14155552345", "stop please"))
+}
+
+func TestMessageRouterDestinationNumbers(t *testing.T) {
+	router := NewMessageRouter()
+	router.Keyword("JOIN", replyWith("joined"))
+	router.Keyword("INFO2", replyWith("info"))
+	router.Default(replyWith("default"))
+
+	support := router.To("+14155550000")
+	support.Keyword("JOIN", replyWith("joined support"))
+	support.Default(replyWith("support default"))
+
+	assert.Equal(t, "joined support", routeReply(t, router, "+14155550000", "JOIN"))
+	assert.Equal(t, "info", routeReply(t, router, "+14155550000", "INFO2"))
+	assert.Equal(t, "support default", routeReply(t, router, "+14155550000", "hello"))
+	assert.Equal(t, "joined", routeReply(t, router, "+14155552345", "JOIN"))
+	assert.Equal(t, "default", routeReply(t, router, "+14155552345", "hello"))
+}
+
+func TestMessageRouterMiddleware(t *testing.T) {
+	var calls []string
+	record := func(name string) IncomingMessageMiddleware {
+		return func(next IncomingMessageHandlerFunc) IncomingMessageHandlerFunc {
+			return func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+				calls = append(calls, name)
+				return next(ctx, msg)
+			}
+		}
+	}
+
+	router := NewMessageRouter()
+	router.Use(record("outer"), record("inner"))
+	router.To("+14155550000").Use(record("number"))
+	router.To("+14155550000").Keyword("JOIN", replyWith("joined"))
+
+	assert.Equal(t, "joined", routeReply(t, router, "+14155550000", "JOIN"))
+	assert.Equal(t, []string{"outer", "inner", "number"}, calls)
+
+	calls = nil
+	assert.Equal(t, "", routeReply(t, router, "+14155550000", "STOP"))
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+}
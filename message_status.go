@@ -0,0 +1,193 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MessageStatus is the delivery status of a message. It is reported in
+// status callbacks and in SMSSendMessageResponse.Status.
+type MessageStatus string
+
+// The statuses a message can have.
+const (
+	MessageStatusAccepted           MessageStatus = "accepted"
+	MessageStatusScheduled          MessageStatus = "scheduled"
+	MessageStatusCanceled           MessageStatus = "canceled"
+	MessageStatusQueued             MessageStatus = "queued"
+	MessageStatusSending            MessageStatus = "sending"
+	MessageStatusSent               MessageStatus = "sent"
+	MessageStatusFailed             MessageStatus = "failed"
+	MessageStatusDelivered          MessageStatus = "delivered"
+	MessageStatusUndelivered        MessageStatus = "undelivered"
+	MessageStatusReceiving          MessageStatus = "receiving"
+	MessageStatusReceived           MessageStatus = "received"
+	MessageStatusRead               MessageStatus = "read"
+	MessageStatusPartiallyDelivered MessageStatus = "partially_delivered"
+)
+
+// Final returns true when the status will not change again, except that a
+// delivered message may later be reported as read.
+func (status MessageStatus) Final() bool {
+	switch status {
+	case MessageStatusCanceled,
+		MessageStatusFailed,
+		MessageStatusDelivered,
+		MessageStatusUndelivered,
+		MessageStatusReceived,
+		MessageStatusRead:
+		return true
+	default:
+		return false
+	}
+}
+
+// MessageStatusEvent is a change to the status of a message, as sent by
+// Twilio to a status callback.
+type MessageStatusEvent struct {
+	MessageSID          string
+	AccountSID          string
+	MessagingServiceSID string
+	From                string
+	To                  string
+	Status              MessageStatus
+	ErrorCode           int
+
+	// Channel is the channel the message was sent on, such as sms or
+	// whatsapp, taken from the prefix of the To address.
+	Channel string
+
+	// RawDLRDoneDate is the date the carrier reported the message as
+	// delivered, in the carrier's YYMMDDhhmm format.
+	RawDLRDoneDate string
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseMessageStatusEvent parses the parameters of a status callback.
+func ParseMessageStatusEvent(form url.Values) (*MessageStatusEvent, error) {
+	event := &MessageStatusEvent{
+		MessageSID:          form.Get("MessageSid"),
+		AccountSID:          form.Get("AccountSid"),
+		MessagingServiceSID: form.Get("MessagingServiceSid"),
+		From:                form.Get("From"),
+		To:                  form.Get("To"),
+		Status:              MessageStatus(form.Get("MessageStatus")),
+		RawDLRDoneDate:      form.Get("RawDlrDoneDate"),
+		Channel:             "sms",
+		Form:                form,
+	}
+
+	if event.MessageSID == "" {
+		event.MessageSID = form.Get("SmsSid")
+	}
+
+	if event.Status == "" {
+		event.Status = MessageStatus(form.Get("SmsStatus"))
+	}
+
+	if event.MessageSID == "" {
+		return nil, fmt.Errorf("Missing parameter: MessageSid")
+	}
+
+	if event.Status == "" {
+		return nil, fmt.Errorf("Missing parameter: MessageStatus")
+	}
+
+	if i := strings.Index(event.To, ":"); i > 0 {
+		event.Channel = event.To[:i]
+	}
+
+	var err error
+	event.ErrorCode, err = parseIntParam(form, "ErrorCode")
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// MessageStatusListener is called for each new message status event. When a
+// listener returns an error, Twilio is asked to retry the callback.
+type MessageStatusListener func(ctx context.Context, event *MessageStatusEvent) error
+
+// MessageStatusHandler is an http.Handler for message status callbacks. It
+// validates the request's signature, parses the event, ignores events that
+// have already been handled and dispatches new events to its listeners.
+// Concurrent deliveries of the same event wait for the first to be handled.
+type MessageStatusHandler struct {
+	validator *RequestValidator
+
+	// DedupeWindow is how long a handled event is remembered so that
+	// repeated callbacks for it are ignored.
+	DedupeWindow time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	listeners []MessageStatusListener
+	deduper   *WebhookDeduper
+}
+
+// NewMessageStatusHandler will create a handler that validates requests with
+// the validator. Requests are not validated when the validator is nil.
+func NewMessageStatusHandler(validator *RequestValidator) *MessageStatusHandler {
+	h := &MessageStatusHandler{
+		validator:    validator,
+		DedupeWindow: 24 * time.Hour,
+		Now:          time.Now,
+	}
+
+	now := func() time.Time { return h.Now() }
+	store := NewMemoryWebhookDedupeStore()
+	store.Now = now
+	h.deduper = NewWebhookDeduper(store)
+	h.deduper.Now = now
+	return h
+}
+
+// AddListener registers a listener for new events.
+func (h *MessageStatusHandler) AddListener(listener MessageStatusListener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+}
+
+func (h *MessageStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	event, err := ParseMessageStatusEvent(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := event.MessageSID + "/" + string(event.Status)
+	h.deduper.serve(w, key, h.DedupeWindow, func(w http.ResponseWriter) {
+		h.mu.Lock()
+		listeners := append([]MessageStatusListener{}, h.listeners...)
+		h.mu.Unlock()
+
+		for _, listener := range listeners {
+			err := listener(r.Context(), event)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,148 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMessageStatusEvent(t *testing.T) {
+	event, err := ParseMessageStatusEvent(url.Values{
+		"MessageSid":     {"SM1"},
+		"MessageStatus":  {"undelivered"},
+		"ErrorCode":      {"30003"},
+		"From":           {"whatsapp:+14155552345"},
+		"To":             {"whatsapp:+15108675310"},
+		"RawDlrDoneDate": {"1906011200"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SM1", event.MessageSID)
+	assert.Equal(t, MessageStatusUndelivered, event.Status)
+	assert.True(t, event.Status.Final())
+	assert.Equal(t, 30003, event.ErrorCode)
+	assert.Equal(t, "whatsapp", event.Channel)
+	assert.Equal(t, "1906011200", event.RawDLRDoneDate)
+
+	event, err = ParseMessageStatusEvent(url.Values{
+		"SmsSid":    {"SM1"},
+		"SmsStatus": {"sent"},
+		"To":        {"+15108675310"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, MessageStatusSent, event.Status)
+	assert.False(t, event.Status.Final())
+	assert.Equal(t, "sms", event.Channel)
+
+	_, err = ParseMessageStatusEvent(url.Values{"MessageSid": {"SM1"}})
+	assert.Equal(t, "Missing parameter: MessageStatus", err.Error())
+}
+
+func TestMessageStatusHandlerDeduplicatesEvents(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	handler := NewMessageStatusHandler(NewRequestValidator("token"))
+	handler.Now = func() time.Time {
+		return now
+	}
+
+	var statuses []MessageStatus
+	handler.AddListener(func(ctx context.Context, event *MessageStatusEvent) error {
+		statuses = append(statuses, event.Status)
+		return nil
+	})
+
+	send := func(status string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/status", url.Values{
+			"MessageSid":    {"SM1"},
+			"MessageStatus": {status},
+		}))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("sent"))
+	assert.Equal(t, http.StatusNoContent, send("sent"))
+	assert.Equal(t, http.StatusNoContent, send("delivered"))
+	assert.Equal(t, []MessageStatus{MessageStatusSent, MessageStatusDelivered}, statuses)
+
+	now = now.Add(25 * time.Hour)
+	assert.Equal(t, http.StatusNoContent, send("sent"))
+	assert.Len(t, statuses, 3)
+}
+
+func TestMessageStatusHandlerDeduplicatesConcurrentEvents(t *testing.T) {
+	handler := NewMessageStatusHandler(nil)
+
+	var calls int32
+	release := make(chan struct{})
+	handler.AddListener(func(ctx context.Context, event *MessageStatusEvent) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil
+	})
+
+	form := url.Values{
+		"MessageSid":    {"SM1"},
+		"MessageStatus": {"delivered"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newSignedRequest("", "http://example.com/status", form))
+			assert.Equal(t, http.StatusNoContent, w.Code)
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMessageStatusHandlerRetriesFailedListeners(t *testing.T) {
+	handler := NewMessageStatusHandler(nil)
+
+	fail := true
+	calls := 0
+	handler.AddListener(func(ctx context.Context, event *MessageStatusEvent) error {
+		calls++
+		if fail {
+			return errors.New("test error")
+		}
+
+		return nil
+	})
+
+	form := url.Values{
+		"MessageSid":    {"SM1"},
+		"MessageStatus": {"delivered"},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/status", form))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	fail = false
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/status", form))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 2, calls)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/status", url.Values{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,266 @@
+package twilio
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// OutboxState is the state of a message in the outbox.
+type OutboxState string
+
+const (
+	// OutboxPending messages are waiting to be sent.
+	OutboxPending OutboxState = "pending"
+
+	// OutboxSending messages were being sent when they were last stored. If
+	// the process stopped while sending, the outcome is unknown. Without
+	// Idempotency the message is sent again, so it may be delivered twice.
+	// With Idempotency, Twilio is checked for the message before it is
+	// sent again.
+	OutboxSending OutboxState = "sending"
+
+	// OutboxSent messages were accepted by Twilio.
+	OutboxSent OutboxState = "sent"
+
+	// OutboxFailed messages could not be sent and will not be retried.
+	OutboxFailed OutboxState = "failed"
+)
+
+// OutboxMessage is a message that has been queued in the outbox.
+type OutboxMessage struct {
+	ID            string                  `json:"id"`
+	From          string                  `json:"from"`
+	To            string                  `json:"to"`
+	Body          string                  `json:"body"`
+	State         OutboxState             `json:"state"`
+	Attempts      int                     `json:"attempts"`
+	CreatedAt     time.Time               `json:"created_at"`
+	NextAttemptAt time.Time               `json:"next_attempt_at"`
+	LastError     string                  `json:"last_error,omitempty"`
+	Response      *SMSSendMessageResponse `json:"response,omitempty"`
+}
+
+// OutboxStore persists the messages in an outbox.
+type OutboxStore interface {
+	// Put inserts or replaces a message.
+	Put(msg *OutboxMessage) error
+
+	// Get returns a message by ID or nil when the message does not exist.
+	Get(id string) (*OutboxMessage, error)
+
+	// Delete removes a message.
+	Delete(id string) error
+
+	// Unsent returns the messages that are pending or were being sent.
+	Unsent() ([]*OutboxMessage, error)
+}
+
+// Outbox persists outgoing messages before sending them so that they are not
+// lost if the process stops. Messages are delivered at least once unless
+// Idempotency is set.
+type Outbox struct {
+	client Client
+	store  OutboxStore
+
+	// MaxAttempts is the number of times a message is sent before it is
+	// marked as failed.
+	MaxAttempts int
+
+	// Backoff returns how long to wait before retrying a message that has
+	// been attempted the given number of times.
+	Backoff func(attempts int) time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// OnResult is called when a message is sent or fails permanently.
+	OnResult func(*OutboxMessage)
+
+	// Idempotency, when set, sends messages using their ID as the
+	// idempotency key so that messages interrupted by a restart are not sent
+	// twice.
+	Idempotency *IdempotentSender
+
+	mu sync.Mutex
+}
+
+// NewOutbox will create an outbox that sends messages with the client and
+// stores them in the store.
+func NewOutbox(client Client, store OutboxStore) *Outbox {
+	return &Outbox{
+		client:      client,
+		store:       store,
+		MaxAttempts: 5,
+		Backoff:     exponentialBackoff,
+		Now:         time.Now,
+	}
+}
+
+// Enqueue stores a message to be sent by the next dispatch.
+func (o *Outbox) Enqueue(from, to, body string) (*OutboxMessage, error) {
+	id, err := newOutboxID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := o.Now()
+	msg := &OutboxMessage{
+		ID:            id,
+		From:          from,
+		To:            to,
+		Body:          body,
+		State:         OutboxPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+
+	err = o.store.Put(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// Dispatch sends every unsent message that is due and returns the number of
+// messages that were attempted. Messages that were being sent when the
+// process stopped are sent again.
+func (o *Outbox) Dispatch() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	msgs, err := o.store.Unsent()
+	if err != nil {
+		return 0, err
+	}
+
+	now := o.Now()
+	attempted := 0
+	for _, msg := range msgs {
+		if msg.State == OutboxPending && msg.NextAttemptAt.After(now) {
+			continue
+		}
+
+		attempted++
+		err = o.send(msg)
+		if err != nil {
+			return attempted, err
+		}
+	}
+
+	return attempted, nil
+}
+
+// Run dispatches messages every interval until the context is done.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := o.Dispatch()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// send attempts to send a message, recording the attempt before calling
+// Twilio and the outcome afterwards. Errors are only returned when the store
+// fails.
+func (o *Outbox) send(msg *OutboxMessage) error {
+	interrupted := msg.State == OutboxSending
+	msg.State = OutboxSending
+	msg.Attempts++
+	err := o.store.Put(msg)
+	if err != nil {
+		return err
+	}
+
+	var resp *SMSSendMessageResponse
+	if o.Idempotency != nil {
+		if interrupted {
+			resp, err = o.Idempotency.Recover(
+				msg.ID,
+				msg.From,
+				msg.To,
+				msg.Body,
+				msg.CreatedAt)
+		}
+
+		if err == nil && resp == nil {
+			resp, err = o.Idempotency.SendSMSMessage(msg.ID, msg.From, msg.To, msg.Body)
+		}
+	} else {
+		resp, err = o.client.SendSMSMessage(msg.From, msg.To, msg.Body)
+	}
+
+	switch {
+	case err == nil:
+		msg.State = OutboxSent
+		msg.Response = resp
+		msg.LastError = ""
+
+	case isTemporary(err) && msg.Attempts < o.MaxAttempts:
+		msg.State = OutboxPending
+		msg.NextAttemptAt = o.Now().Add(o.Backoff(msg.Attempts))
+		msg.LastError = err.Error()
+
+	default:
+		msg.State = OutboxFailed
+		msg.LastError = err.Error()
+	}
+
+	err = o.store.Put(msg)
+	if err != nil {
+		return err
+	}
+
+	if msg.State != OutboxPending && o.OnResult != nil {
+		o.OnResult(msg)
+	}
+
+	return nil
+}
+
+// isTemporary returns true for errors that may not recur if the request is
+// retried. Network errors are assumed to be temporary. Errors decoding a
+// response are not, because Twilio accepted the request.
+func isTemporary(err error) bool {
+	switch err := err.(type) {
+	case *UnexpectedResponseError:
+		return err.Temporary()
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return false
+	default:
+		return true
+	}
+}
+
+func exponentialBackoff(attempts int) time.Duration {
+	d := time.Second << uint(attempts-1)
+	if d <= 0 || d > 5*time.Minute {
+		return 5 * time.Minute
+	}
+
+	return d
+}
+
+func newOutboxID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,376 @@
+package twilio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MemoryOutboxStore keeps outbox messages in memory. It is useful for tests
+// and for processes that do not need to survive a restart.
+type MemoryOutboxStore struct {
+	mu       sync.Mutex
+	messages map[string]*OutboxMessage
+}
+
+// NewMemoryOutboxStore will create an empty in-memory store.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{
+		messages: map[string]*OutboxMessage{},
+	}
+}
+
+// Put inserts or replaces a message.
+func (store *MemoryOutboxStore) Put(msg *OutboxMessage) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	copied := *msg
+	store.messages[msg.ID] = &copied
+	return nil
+}
+
+// Get returns a message by ID or nil when the message does not exist.
+func (store *MemoryOutboxStore) Get(id string) (*OutboxMessage, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	msg, ok := store.messages[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *msg
+	return &copied, nil
+}
+
+// Delete removes a message.
+func (store *MemoryOutboxStore) Delete(id string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.messages, id)
+	return nil
+}
+
+// Unsent returns the messages that are pending or were being sent, oldest
+// first.
+func (store *MemoryOutboxStore) Unsent() ([]*OutboxMessage, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return unsentOutboxMessages(store.messages), nil
+}
+
+// FileOutboxStore keeps outbox messages in an append-only log file. Every
+// change is appended and synced to disk before it is acknowledged, and the
+// log is replayed when the store is opened.
+type FileOutboxStore struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	messages map[string]*OutboxMessage
+	entries  int
+	sent     int
+}
+
+// minCompactEntries is the smallest log that is compacted automatically.
+// Larger logs are compacted once they have four entries for each unsent
+// message.
+const minCompactEntries = 1000
+
+type outboxLogEntry struct {
+	Message *OutboxMessage `json:"message,omitempty"`
+	Deleted string         `json:"deleted,omitempty"`
+}
+
+// OpenFileOutboxStore will open or create the log file at path and replay
+// it. An incomplete entry at the end of the log, left by a process that
+// stopped while writing, is ignored. An error is returned, and the log is
+// left unchanged, when any other entry is corrupt.
+func OpenFileOutboxStore(path string) (*FileOutboxStore, error) {
+	store := &FileOutboxStore{
+		path:     path,
+		messages: map[string]*OutboxMessage{},
+	}
+
+	err := store.replay()
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.Compact()
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Put inserts or replaces a message.
+func (store *FileOutboxStore) Put(msg *OutboxMessage) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	copied := *msg
+	err := store.append(&outboxLogEntry{Message: &copied})
+	if err != nil {
+		return err
+	}
+
+	store.countSent(store.messages[msg.ID], -1)
+	store.countSent(&copied, 1)
+	store.messages[msg.ID] = &copied
+	return store.maybeCompact()
+}
+
+// Get returns a message by ID or nil when the message does not exist.
+func (store *FileOutboxStore) Get(id string) (*OutboxMessage, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	msg, ok := store.messages[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *msg
+	return &copied, nil
+}
+
+// Delete removes a message.
+func (store *FileOutboxStore) Delete(id string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	err := store.append(&outboxLogEntry{Deleted: id})
+	if err != nil {
+		return err
+	}
+
+	store.countSent(store.messages[id], -1)
+	delete(store.messages, id)
+	return store.maybeCompact()
+}
+
+// Unsent returns the messages that are pending or were being sent, oldest
+// first.
+func (store *FileOutboxStore) Unsent() ([]*OutboxMessage, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return unsentOutboxMessages(store.messages), nil
+}
+
+// Compact rewrites the log so that it only contains the current state of
+// each message that has not been sent. Sent messages are forgotten. The log
+// is also compacted automatically as it grows.
+func (store *FileOutboxStore) Compact() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.compact()
+}
+
+// maybeCompact compacts the log once it is mostly replaced or deleted
+// entries.
+func (store *FileOutboxStore) maybeCompact() error {
+	if store.entries < minCompactEntries || store.entries < 4*(len(store.messages)-store.sent) {
+		return nil
+	}
+
+	return store.compact()
+}
+
+func (store *FileOutboxStore) countSent(msg *OutboxMessage, delta int) {
+	if msg != nil && msg.State == OutboxSent {
+		store.sent += delta
+	}
+}
+
+func (store *FileOutboxStore) compact() error {
+	for id, msg := range store.messages {
+		if msg.State == OutboxSent {
+			delete(store.messages, id)
+		}
+	}
+
+	tmp, err := os.OpenFile(
+		store.path+".tmp",
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, msg := range sortedOutboxMessages(store.messages) {
+		err = enc.Encode(&outboxLogEntry{Message: msg})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	if store.file != nil {
+		store.file.Close()
+		store.file = nil
+	}
+
+	err = os.Rename(store.path+".tmp", store.path)
+	if err != nil {
+		return err
+	}
+
+	err = syncDir(filepath.Dir(store.path))
+	if err != nil {
+		return err
+	}
+
+	store.file, err = os.OpenFile(store.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	store.entries = len(store.messages)
+	store.sent = 0
+	return nil
+}
+
+// Close closes the log file.
+func (store *FileOutboxStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.file == nil {
+		return nil
+	}
+
+	err := store.file.Close()
+	store.file = nil
+	return err
+}
+
+func (store *FileOutboxStore) replay() error {
+	f, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if err == io.EOF && len(bytes.TrimSpace(b)) == 0 {
+			return nil
+		}
+
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		var entry outboxLogEntry
+		decodeErr := json.Unmarshal(b, &entry)
+		if decodeErr != nil {
+			// Only the final entry can be incomplete. Entries are written
+			// with their newline, so it does not end with one.
+			if err == io.EOF {
+				return nil
+			}
+
+			return fmt.Errorf("Corrupt outbox log entry on line %d: %s", line, decodeErr)
+		}
+
+		switch {
+		case entry.Message != nil:
+			store.messages[entry.Message.ID] = entry.Message
+		case entry.Deleted != "":
+			delete(store.messages, entry.Deleted)
+		}
+
+		store.entries++
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+func (store *FileOutboxStore) append(entry *outboxLogEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.file.Write(append(b, '\n'))
+	if err != nil {
+		return err
+	}
+
+	store.entries++
+	return store.file.Sync()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// Not every platform supports syncing a directory.
+	d.Sync()
+	return nil
+}
+
+func unsentOutboxMessages(messages map[string]*OutboxMessage) []*OutboxMessage {
+	unsent := map[string]*OutboxMessage{}
+	for id, msg := range messages {
+		if msg.State == OutboxPending || msg.State == OutboxSending {
+			copied := *msg
+			unsent[id] = &copied
+		}
+	}
+
+	return sortedOutboxMessages(unsent)
+}
+
+func sortedOutboxMessages(messages map[string]*OutboxMessage) []*OutboxMessage {
+	sorted := make([]*OutboxMessage, 0, len(messages))
+	for _, msg := range messages {
+		sorted = append(sorted, msg)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	return sorted
+}
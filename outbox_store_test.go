@@ -0,0 +1,133 @@
+// +build unit
+
+package twilio
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryOutboxStore(t *testing.T) {
+	store := NewMemoryOutboxStore()
+
+	msg := &OutboxMessage{ID: "1", State: OutboxPending}
+	assert.NoError(t, store.Put(msg))
+
+	// The store keeps its own copy.
+	msg.State = OutboxSent
+	stored, err := store.Get("1")
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxPending, stored.State)
+
+	unsent, err := store.Unsent()
+	assert.NoError(t, err)
+	assert.Len(t, unsent, 1)
+
+	assert.NoError(t, store.Delete("1"))
+	stored, err = store.Get("1")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestFileOutboxStorePersistsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	store, err := OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+
+	created := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Put(&OutboxMessage{ID: "2", State: OutboxPending, CreatedAt: created.Add(time.Second)}))
+	assert.NoError(t, store.Put(&OutboxMessage{ID: "1", State: OutboxPending, CreatedAt: created}))
+	assert.NoError(t, store.Put(&OutboxMessage{ID: "3", State: OutboxPending, CreatedAt: created}))
+	assert.NoError(t, store.Put(&OutboxMessage{ID: "3", State: OutboxSent, CreatedAt: created}))
+	assert.NoError(t, store.Delete("2"))
+	assert.NoError(t, store.Close())
+
+	store, err = OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	unsent, err := store.Unsent()
+	assert.NoError(t, err)
+	assert.Len(t, unsent, 1)
+	assert.Equal(t, "1", unsent[0].ID)
+
+	// Sent messages are dropped when the log is compacted.
+	stored, err := store.Get("3")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	stored, err = store.Get("2")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestFileOutboxStoreIgnoresIncompleteEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	err := ioutil.WriteFile(path, []byte(
+		`{"message":{"id":"1","state":"pending"}}`+"\n"+`{"message":{"id":"2","st`),
+		0600)
+	assert.NoError(t, err)
+
+	store, err := OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	unsent, err := store.Unsent()
+	assert.NoError(t, err)
+	assert.Len(t, unsent, 1)
+	assert.Equal(t, "1", unsent[0].ID)
+
+	// Opening the store compacts away the incomplete entry.
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"2"`)
+}
+
+func TestFileOutboxStoreRejectsCorruptEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	log := `{"message":{"id":"1","state":"pending"}}` + "\n" +
+		`not json` + "\n" +
+		`{"message":{"id":"2","state":"pending"}}` + "\n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte(log), 0600))
+
+	_, err := OpenFileOutboxStore(path)
+	assert.EqualError(t, err, "Corrupt outbox log entry on line 2: invalid character 'o' in literal null (expecting 'u')")
+
+	// The log is left for the corruption to be repaired.
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, log, string(b))
+}
+
+func TestFileOutboxStoreCompactsAutomatically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	store, err := OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&OutboxMessage{ID: "pending", State: OutboxPending}))
+	for i := 0; i < minCompactEntries; i++ {
+		assert.NoError(t, store.Put(&OutboxMessage{ID: fmt.Sprint(i), State: OutboxSent}))
+	}
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, len(bytes.Split(bytes.TrimSpace(b), []byte("\n"))) < minCompactEntries)
+
+	unsent, err := store.Unsent()
+	assert.NoError(t, err)
+	assert.Len(t, unsent, 1)
+}
+
+func TestOpenFileOutboxStoreWithMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "outbox.log")
+	_, err := OpenFileOutboxStore(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,184 @@
+// +build unit
+
+package twilio
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxDispatchUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello!", r.Form.Get("Body"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(NewClient(opts), store)
+
+	var results []*OutboxMessage
+	outbox.OnResult = func(msg *OutboxMessage) {
+		results = append(results, msg)
+	}
+
+	msg, err := outbox.Enqueue("+14155552345", "+15108675310", "Hello!")
+	assert.NoError(t, err)
+	assert.Len(t, msg.ID, 32)
+	assert.Equal(t, OutboxPending, msg.State)
+
+	n, err := outbox.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	stored, err := store.Get(msg.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxSent, stored.State)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "queued", stored.Response.Status)
+	assert.Len(t, results, 1)
+
+	n, err = outbox.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestOutboxRetriesTemporaryErrorsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(NewClient(opts), store)
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	outbox.Now = func() time.Time {
+		return now
+	}
+
+	msg, err := outbox.Enqueue("+14155552345", "+15108675310", "Hello!")
+	assert.NoError(t, err)
+
+	_, err = outbox.Dispatch()
+	assert.NoError(t, err)
+
+	stored, _ := store.Get(msg.ID)
+	assert.Equal(t, OutboxPending, stored.State)
+	assert.Equal(t, "Unexpected response. Expected 200 but found 503", stored.LastError)
+	assert.Equal(t, now.Add(time.Second), stored.NextAttemptAt)
+
+	n, err := outbox.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	now = now.Add(time.Second)
+	n, err = outbox.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	stored, _ = store.Get(msg.ID)
+	assert.Equal(t, OutboxSent, stored.State)
+	assert.Equal(t, 2, stored.Attempts)
+}
+
+func TestOutboxFailsPermanentErrorsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(NewClient(opts), store)
+
+	var result *OutboxMessage
+	outbox.OnResult = func(msg *OutboxMessage) {
+		result = msg
+	}
+
+	msg, err := outbox.Enqueue("+14155552345", "+15108675310", "Hello!")
+	assert.NoError(t, err)
+
+	_, err = outbox.Dispatch()
+	assert.NoError(t, err)
+
+	assert.Equal(t, msg.ID, result.ID)
+	assert.Equal(t, OutboxFailed, result.State)
+	assert.Equal(t, "Unexpected response. Expected 200 but found 400", result.LastError)
+}
+
+func TestOutboxReplaysAfterRestartUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	var bodies []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		bodies = append(bodies, r.Form.Get("Body"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	store, err := OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	outbox := NewOutbox(NewClient(opts), store)
+
+	_, err = outbox.Enqueue("+14155552345", "+15108675310", "first")
+	assert.NoError(t, err)
+	interrupted, err := outbox.Enqueue("+14155552345", "+15108675310", "second")
+	assert.NoError(t, err)
+
+	// Simulate the process stopping while the second message was being sent.
+	interrupted.State = OutboxSending
+	interrupted.Attempts = 1
+	assert.NoError(t, store.Put(interrupted))
+	assert.NoError(t, store.Close())
+
+	store, err = OpenFileOutboxStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	outbox = NewOutbox(NewClient(opts), store)
+	n, err := outbox.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"first", "second"}, bodies)
+
+	unsent, err := store.Unsent()
+	assert.NoError(t, err)
+	assert.Empty(t, unsent)
+}
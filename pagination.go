@@ -0,0 +1,75 @@
+package twilio
+
+import "net/url"
+
+// page is a single page of a list response.
+type page interface {
+	nextPageURL() string
+}
+
+// pageMeta is the pagination metadata included in list responses from
+// Twilio's newer APIs, such as Conversations.
+type pageMeta struct {
+	Page        int    `json:"page"`
+	PageSize    int    `json:"page_size"`
+	URL         string `json:"url"`
+	NextPageURL string `json:"next_page_url"`
+	Key         string `json:"key"`
+}
+
+// apiPageMeta is the pagination metadata included in list responses from
+// Twilio's REST API. The next page is a URI relative to the API's host.
+type apiPageMeta struct {
+	NextPageURI string `json:"next_page_uri"`
+}
+
+// pager fetches the pages of a list response one at a time.
+type pager struct {
+	client  *clientImpl
+	nextURL string
+	err     error
+}
+
+func newPager(client *clientImpl, firstURL string) pager {
+	return pager{
+		client:  client,
+		nextURL: firstURL,
+	}
+}
+
+// fetch reads the next page into p. It returns false when there are no more
+// pages or an error occurred.
+func (p *pager) fetch(pg page) bool {
+	if p.nextURL == "" || p.err != nil {
+		return false
+	}
+
+	err := p.client.fetch(p.nextURL, pg)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.nextURL, p.err = resolvePageURL(p.nextURL, pg.nextPageURL())
+	return p.err == nil
+}
+
+// resolvePageURL resolves the URL of the next page, which may be relative,
+// against the URL of the current page.
+func resolvePageURL(current string, next string) (string, error) {
+	if next == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := url.Parse(next)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,53 @@
+package twilio
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func setValue(v url.Values, key string, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
+
+func setValues(v url.Values, key string, values []string) {
+	for _, value := range values {
+		v.Add(key, value)
+	}
+}
+
+func setInt(v url.Values, key string, value int) {
+	if value != 0 {
+		v.Set(key, strconv.Itoa(value))
+	}
+}
+
+// setOptionalInt sets an integer that may be zero.
+func setOptionalInt(v url.Values, key string, value *int) {
+	if value != nil {
+		v.Set(key, strconv.Itoa(*value))
+	}
+}
+
+func setBool(v url.Values, key string, value *bool) {
+	if value != nil {
+		v.Set(key, strconv.FormatBool(*value))
+	}
+}
+
+// setDate sets a date in the YYYY-MM-DD format used by list filters.
+func setDate(v url.Values, key string, value time.Time) {
+	if !value.IsZero() {
+		v.Set(key, value.UTC().Format("2006-01-02"))
+	}
+}
+
+// setDuration sets a duration using ISO 8601 notation, which is how Twilio
+// expects timers to be expressed.
+func setDuration(v url.Values, key string, value time.Duration) {
+	if value > 0 {
+		v.Set(key, "PT"+strconv.FormatInt(int64(value/time.Second), 10)+"S")
+	}
+}
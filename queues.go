@@ -0,0 +1,271 @@
+package twilio
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// QueueFront can be used as the call SID of the member at the front of a
+// queue.
+const QueueFront = "Front"
+
+// Queue is a queue of calls waiting to be connected.
+type Queue struct {
+	SID             string `json:"sid"`
+	AccountSID      string `json:"account_sid"`
+	FriendlyName    string `json:"friendly_name"`
+	CurrentSize     int    `json:"current_size"`
+	MaxSize         int    `json:"max_size"`
+	AverageWaitTime int    `json:"average_wait_time"`
+
+	// The dates are in RFC1123Z format.
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// QueueParams are the parameters for creating or updating a queue. Empty
+// values are not sent.
+type QueueParams struct {
+	FriendlyName string
+
+	// MaxSize is the maximum number of calls in the queue. Twilio defaults
+	// to 100 and allows at most 5000.
+	MaxSize int
+}
+
+func (params *QueueParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "FriendlyName", params.FriendlyName)
+	setInt(v, "MaxSize", params.MaxSize)
+	return v
+}
+
+// QueueListParams are the parameters for listing queues. Empty values are
+// not sent.
+type QueueListParams struct {
+	PageSize int
+}
+
+func (params *QueueListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+// QueueMemberListParams are the parameters for listing the members of a
+// queue. Empty values are not sent.
+type QueueMemberListParams struct {
+	PageSize int
+}
+
+func (params *QueueMemberListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+// QueueMember is a call waiting in a queue.
+type QueueMember struct {
+	CallSID  string `json:"call_sid"`
+	QueueSID string `json:"queue_sid"`
+
+	// Position is the member's position in the queue, starting at 1.
+	Position int `json:"position"`
+
+	// WaitTime is how long the member has waited in seconds.
+	WaitTime int `json:"wait_time"`
+
+	// DateEnqueued is in RFC1123Z format.
+	DateEnqueued string `json:"date_enqueued"`
+}
+
+type queuePage struct {
+	Queues []*Queue `json:"queues"`
+	apiPageMeta
+}
+
+func (p *queuePage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// QueueIterator iterates over a list of queues, fetching pages as needed.
+type QueueIterator struct {
+	pager   pager
+	items   []*Queue
+	current *Queue
+}
+
+// Next advances to the next queue. It returns false when there are no more
+// queues or an error occurred.
+func (it *QueueIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p queuePage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Queues
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Queue returns the current queue.
+func (it *QueueIterator) Queue() *Queue {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *QueueIterator) Err() error {
+	return it.pager.err
+}
+
+type queueMemberPage struct {
+	QueueMembers []*QueueMember `json:"queue_members"`
+	apiPageMeta
+}
+
+func (p *queueMemberPage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// QueueMemberIterator iterates over the members of a queue, from the front,
+// fetching pages as needed.
+type QueueMemberIterator struct {
+	pager   pager
+	items   []*QueueMember
+	current *QueueMember
+}
+
+// Next advances to the next member. It returns false when there are no more
+// members or an error occurred.
+func (it *QueueMemberIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p queueMemberPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.QueueMembers
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Member returns the current member.
+func (it *QueueMemberIterator) Member() *QueueMember {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *QueueMemberIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) CreateQueue(params *QueueParams) (*Queue, error) {
+	var queue Queue
+	err := client.create(client.accountURL("Queues"), params.values(), &queue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &queue, nil
+}
+
+func (client *clientImpl) FetchQueue(sid string) (*Queue, error) {
+	var queue Queue
+	err := client.fetch(client.accountURL("Queues", sid), &queue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &queue, nil
+}
+
+func (client *clientImpl) ListQueues(params *QueueListParams) *QueueIterator {
+	requestURL := client.accountURL("Queues")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &QueueIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) UpdateQueue(sid string, params *QueueParams) (*Queue, error) {
+	var queue Queue
+	err := client.update(client.accountURL("Queues", sid), params.values(), &queue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &queue, nil
+}
+
+func (client *clientImpl) DeleteQueue(sid string) error {
+	return client.remove(client.accountURL("Queues", sid))
+}
+
+func (client *clientImpl) FetchQueueMember(queueSID string, callSID string) (*QueueMember, error) {
+	var member QueueMember
+	err := client.fetch(client.accountURL("Queues", queueSID, "Members", callSID), &member)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (client *clientImpl) ListQueueMembers(
+	queueSID string,
+	params *QueueMemberListParams,
+) *QueueMemberIterator {
+	requestURL := client.accountURL("Queues", queueSID, "Members")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &QueueMemberIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) DequeueMember(
+	queueSID string,
+	callSID string,
+	twimlURL string,
+	method string,
+) (*QueueMember, error) {
+	v := url.Values{}
+	setValue(v, "Url", twimlURL)
+	setValue(v, "Method", method)
+
+	var member QueueMember
+	err := client.update(client.accountURL("Queues", queueSID, "Members", callSID), v, &member)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
@@ -0,0 +1,142 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueOperationsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Queues.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, url.Values{"FriendlyName": {"support"}, "MaxSize": {"50"}}, r.PostForm)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sid": "QU1", "friendly_name": "support", "max_size": 50}`))
+
+		case http.MethodGet:
+			assert.Equal(t, "20", r.URL.Query().Get("PageSize"))
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"queues": [{"sid": "QU1"}, {"sid": "QU2"}], "next_page_uri": null}`)
+		}
+	})
+
+	mux.HandleFunc("/Accounts/sid/Queues/QU1.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "QU1", "current_size": 3, "average_wait_time": 42}`))
+
+		case http.MethodPost:
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, url.Values{"MaxSize": {"100"}}, r.PostForm)
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "QU1", "max_size": 100}`))
+
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	queue, err := client.CreateQueue(&QueueParams{FriendlyName: "support", MaxSize: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, &Queue{SID: "QU1", FriendlyName: "support", MaxSize: 50}, queue)
+
+	queue, err = client.FetchQueue("QU1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, queue.CurrentSize)
+	assert.Equal(t, 42, queue.AverageWaitTime)
+
+	queue, err = client.UpdateQueue("QU1", &QueueParams{MaxSize: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, queue.MaxSize)
+
+	var sids []string
+	it := client.ListQueues(&QueueListParams{PageSize: 20})
+	for it.Next() {
+		sids = append(sids, it.Queue().SID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"QU1", "QU2"}, sids)
+
+	assert.NoError(t, client.DeleteQueue("QU1"))
+}
+
+func TestQueueParamsNil(t *testing.T) {
+	var params *QueueParams
+	assert.Empty(t, params.values())
+
+	var listParams *QueueListParams
+	assert.Empty(t, listParams.values())
+
+	var memberListParams *QueueMemberListParams
+	assert.Empty(t, memberListParams.values())
+}
+
+func TestQueueMemberOperationsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Queues/QU1/Members.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "50", r.URL.Query().Get("PageSize"))
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{
+	"queue_members": [
+		{"call_sid": "CA1", "position": 1, "wait_time": 30},
+		{"call_sid": "CA2", "position": 2, "wait_time": 10}
+	],
+	"next_page_uri": null
+}`)
+	})
+
+	mux.HandleFunc("/Accounts/sid/Queues/QU1/Members/Front.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{"Url": {"https://example.com/agent"}, "Method": {"POST"}}, r.PostForm)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"call_sid": "CA1", "queue_sid": "QU1", "position": 1}`))
+	})
+
+	mux.HandleFunc("/Accounts/sid/Queues/QU1/Members/CA2.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"call_sid": "CA2", "queue_sid": "QU1", "position": 2}`))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+
+	var members []*QueueMember
+	it := client.ListQueueMembers("QU1", &QueueMemberListParams{PageSize: 50})
+	for it.Next() {
+		members = append(members, it.Member())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []*QueueMember{
+		{CallSID: "CA1", Position: 1, WaitTime: 30},
+		{CallSID: "CA2", Position: 2, WaitTime: 10},
+	}, members)
+
+	member, err := client.FetchQueueMember("QU1", "CA2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, member.Position)
+
+	member, err = client.DequeueMember("QU1", QueueFront, "https://example.com/agent", "POST")
+	assert.NoError(t, err)
+	assert.Equal(t, "CA1", member.CallSID)
+}
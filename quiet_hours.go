@@ -0,0 +1,351 @@
+package twilio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// MessageCategory classifies an outgoing message so that the appropriate
+// quiet hours policy can be applied to it.
+type MessageCategory string
+
+const (
+	// MessageCategoryUrgent is used for messages that must always be
+	// delivered immediately, such as one-time passcodes and security alerts.
+	MessageCategoryUrgent MessageCategory = "urgent"
+
+	// MessageCategoryTransactional is used for messages the recipient is
+	// expecting, such as order and appointment updates.
+	MessageCategoryTransactional MessageCategory = "transactional"
+
+	// MessageCategoryMarketing is used for promotional messages.
+	MessageCategoryMarketing MessageCategory = "marketing"
+)
+
+// QuietHoursAction is what the scheduler does with a message that would
+// otherwise be delivered during the recipient's quiet hours.
+type QuietHoursAction int
+
+const (
+	// QuietHoursSendNow ignores quiet hours and sends immediately.
+	QuietHoursSendNow QuietHoursAction = iota
+
+	// QuietHoursDefer holds the message in memory and sends it when the
+	// recipient's quiet hours end.
+	QuietHoursDefer
+
+	// QuietHoursSchedule uses Twilio's scheduled sends to deliver the message
+	// when the recipient's quiet hours end.
+	QuietHoursSchedule
+
+	// QuietHoursReject refuses to send the message.
+	QuietHoursReject
+)
+
+// ErrQuietHours is returned when a message is rejected because it would be
+// delivered during the recipient's quiet hours.
+var ErrQuietHours = errors.New("Message would be delivered during quiet hours")
+
+// twilioMinimumScheduleDelay is the shortest delay Twilio accepts for a
+// scheduled send.
+const twilioMinimumScheduleDelay = 15 * time.Minute
+
+// QuietHoursPolicy describes when and how quiet hours apply to a category of
+// messages.
+type QuietHoursPolicy struct {
+	// Start and End are offsets from midnight in the recipient's local time.
+	// Quiet hours wrap around midnight when Start is after End. Quiet hours
+	// are disabled when Start and End are equal.
+	Start time.Duration
+	End   time.Duration
+
+	Action QuietHoursAction
+
+	// MessagingServiceSID is the messaging service used to schedule messages
+	// when Action is QuietHoursSchedule.
+	MessagingServiceSID string
+}
+
+// NewQuietHoursPolicy will create a policy for quiet hours between 9pm and
+// 8am with the given action.
+func NewQuietHoursPolicy(action QuietHoursAction) *QuietHoursPolicy {
+	return &QuietHoursPolicy{
+		Start:  21 * time.Hour,
+		End:    8 * time.Hour,
+		Action: action,
+	}
+}
+
+// QuietHoursResult describes what happened to a message.
+type QuietHoursResult struct {
+	// Response is Twilio's response when the message was sent or scheduled
+	// with Twilio. It is nil when the message was deferred.
+	Response *SMSSendMessageResponse
+
+	// Deferred is true when the message is being held in memory until
+	// SendAt.
+	Deferred bool
+
+	// SendAt is when the message will be sent. It is zero when the message
+	// was sent immediately.
+	SendAt time.Time
+}
+
+// QuietHoursDeferredSend reports the outcome of a deferred message.
+type QuietHoursDeferredSend struct {
+	Category MessageCategory
+	From     string
+	To       string
+	Body     string
+	Response *SMSSendMessageResponse
+	Err      error
+}
+
+// QuietHoursScheduler sends messages while respecting quiet hours in the
+// recipient's time zone.
+type QuietHoursScheduler struct {
+	client   Client
+	policies map[MessageCategory]*QuietHoursPolicy
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// OnDeferredSend is called after a deferred message has been sent.
+	OnDeferredSend func(*QuietHoursDeferredSend)
+
+	mu      sync.Mutex
+	pending map[*time.Timer]struct{}
+}
+
+// NewQuietHoursScheduler will create a scheduler that sends urgent and
+// transactional messages immediately and defers marketing messages sent
+// between 9pm and 8am.
+func NewQuietHoursScheduler(client Client) *QuietHoursScheduler {
+	return &QuietHoursScheduler{
+		client: client,
+		policies: map[MessageCategory]*QuietHoursPolicy{
+			MessageCategoryUrgent:        NewQuietHoursPolicy(QuietHoursSendNow),
+			MessageCategoryTransactional: NewQuietHoursPolicy(QuietHoursSendNow),
+			MessageCategoryMarketing:     NewQuietHoursPolicy(QuietHoursDefer),
+		},
+		Now:     time.Now,
+		pending: map[*time.Timer]struct{}{},
+	}
+}
+
+// SetPolicy sets the policy for a category of messages.
+func (s *QuietHoursScheduler) SetPolicy(
+	category MessageCategory,
+	policy *QuietHoursPolicy,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies[category] = policy
+}
+
+// Policy returns the policy for a category of messages. Categories without
+// a policy are sent immediately.
+func (s *QuietHoursScheduler) Policy(category MessageCategory) *QuietHoursPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy, ok := s.policies[category]
+	if !ok {
+		return NewQuietHoursPolicy(QuietHoursSendNow)
+	}
+
+	return policy
+}
+
+// Location infers the recipient's time zone from their phone number, falling
+// back to looking up the phone number's country with Twilio.
+func (s *QuietHoursScheduler) Location(to string) (*time.Location, error) {
+	loc, err := TimeZoneForPhoneNumber(to)
+	if err == nil {
+		return loc, nil
+	}
+
+	resp, err := s.client.LookupPhoneNumber(to, CountryCodeNone, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return TimeZoneForCountry(resp.CountryCode)
+}
+
+// Send sends a message now or later depending on the policy for the
+// category and the time in the recipient's time zone.
+func (s *QuietHoursScheduler) Send(
+	category MessageCategory,
+	from string,
+	to string,
+	body string,
+) (*QuietHoursResult, error) {
+	policy := s.Policy(category)
+	if policy.Action == QuietHoursSendNow {
+		return s.sendNow(from, to, body)
+	}
+
+	loc, err := s.Location(to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Now().In(loc)
+	sendAt, quiet := policy.nextAllowed(now)
+	if !quiet {
+		return s.sendNow(from, to, body)
+	}
+
+	switch policy.Action {
+	case QuietHoursDefer:
+		s.deferSend(sendAt.Sub(now), category, from, to, body)
+		return &QuietHoursResult{
+			Deferred: true,
+			SendAt:   sendAt,
+		}, nil
+
+	case QuietHoursSchedule:
+		if policy.MessagingServiceSID == "" {
+			return nil, errors.New("Scheduling requires a messaging service SID")
+		}
+
+		if sendAt.Sub(now) < twilioMinimumScheduleDelay {
+			sendAt = now.Add(twilioMinimumScheduleDelay)
+		}
+
+		resp, err := s.client.ScheduleSMSMessage(
+			policy.MessagingServiceSID,
+			to,
+			body,
+			sendAt)
+		if err != nil {
+			return nil, err
+		}
+
+		return &QuietHoursResult{
+			Response: resp,
+			SendAt:   sendAt,
+		}, nil
+
+	default:
+		return nil, ErrQuietHours
+	}
+}
+
+// Pending returns the number of deferred messages that have not been sent.
+func (s *QuietHoursScheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.pending)
+}
+
+// Stop cancels all deferred messages that have not been sent.
+func (s *QuietHoursScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for timer := range s.pending {
+		timer.Stop()
+		delete(s.pending, timer)
+	}
+}
+
+func (s *QuietHoursScheduler) sendNow(
+	from string,
+	to string,
+	body string,
+) (*QuietHoursResult, error) {
+	resp, err := s.client.SendSMSMessage(from, to, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuietHoursResult{
+		Response: resp,
+	}, nil
+}
+
+func (s *QuietHoursScheduler) deferSend(
+	delay time.Duration,
+	category MessageCategory,
+	from string,
+	to string,
+	body string,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		_, ok := s.pending[timer]
+		delete(s.pending, timer)
+		s.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		resp, err := s.client.SendSMSMessage(from, to, body)
+		if s.OnDeferredSend != nil {
+			s.OnDeferredSend(&QuietHoursDeferredSend{
+				Category: category,
+				From:     from,
+				To:       to,
+				Body:     body,
+				Response: resp,
+				Err:      err,
+			})
+		}
+	})
+	s.pending[timer] = struct{}{}
+}
+
+// nextAllowed returns when quiet hours end and whether now is within quiet
+// hours.
+func (policy *QuietHoursPolicy) nextAllowed(now time.Time) (time.Time, bool) {
+	if policy.Start == policy.End {
+		return now, false
+	}
+
+	y, m, d := now.Date()
+	hour, min, sec := now.Clock()
+	offset := time.Duration(hour)*time.Hour +
+		time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second
+
+	var quiet bool
+	if policy.Start < policy.End {
+		quiet = offset >= policy.Start && offset < policy.End
+	} else {
+		quiet = offset >= policy.Start || offset < policy.End
+	}
+
+	if !quiet {
+		return now, false
+	}
+
+	end := atOffset(y, m, d, policy.End, now.Location())
+	if !end.After(now) {
+		end = atOffset(y, m, d+1, policy.End, now.Location())
+	}
+
+	return end, true
+}
+
+func atOffset(
+	y int,
+	m time.Month,
+	d int,
+	offset time.Duration,
+	loc *time.Location,
+) time.Time {
+	h := offset / time.Hour
+	min := (offset % time.Hour) / time.Minute
+	sec := (offset % time.Minute) / time.Second
+	return time.Date(y, m, d, int(h), int(min), int(sec), 0, loc)
+}
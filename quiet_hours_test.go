@@ -0,0 +1,238 @@
+// +build unit
+
+package twilio
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeZoneForPhoneNumber(t *testing.T) {
+	loc, err := TimeZoneForPhoneNumber("+14155552345")
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Los_Angeles", loc.String())
+
+	loc, err = TimeZoneForPhoneNumber("+447700900123")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/London", loc.String())
+
+	loc, err = TimeZoneForPhoneNumber("+353851234567")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Dublin", loc.String())
+
+	loc, err = TimeZoneForPhoneNumber("+18765551234")
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Jamaica", loc.String())
+
+	_, err = TimeZoneForPhoneNumber("4155552345")
+	assert.Error(t, err)
+
+	_, err = TimeZoneForPhoneNumber("+19995552345")
+	assert.Error(t, err)
+}
+
+func TestTimeZonesAreValid(t *testing.T) {
+	for _, ctz := range countryTimeZones {
+		_, err := time.LoadLocation(ctz.timeZone)
+		assert.NoError(t, err, ctz.timeZone)
+	}
+
+	for tz := range nanpTimeZones {
+		_, err := time.LoadLocation(tz)
+		assert.NoError(t, err, tz)
+	}
+
+	for code := range nanpAreaCodeCountries {
+		assert.Contains(t, nanpAreaCodeTimeZones, code)
+	}
+}
+
+func TestQuietHoursPolicyNextAllowed(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+	policy := NewQuietHoursPolicy(QuietHoursDefer)
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, loc)
+	_, quiet := policy.nextAllowed(now)
+	assert.False(t, quiet)
+
+	now = time.Date(2019, 6, 1, 23, 30, 0, 0, loc)
+	sendAt, quiet := policy.nextAllowed(now)
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2019, 6, 2, 8, 0, 0, 0, loc), sendAt)
+
+	now = time.Date(2019, 6, 1, 3, 0, 0, 0, loc)
+	sendAt, quiet = policy.nextAllowed(now)
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2019, 6, 1, 8, 0, 0, 0, loc), sendAt)
+
+	policy.Start = policy.End
+	_, quiet = policy.nextAllowed(now)
+	assert.False(t, quiet)
+}
+
+func TestQuietHoursSchedulerSendsUrgentMessagesUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	s := NewQuietHoursScheduler(NewClient(opts))
+	s.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 7, 0, 0, 0, time.UTC)
+	}
+
+	result, err := s.Send(
+		MessageCategoryUrgent,
+		"+14155552345",
+		"+15108675310",
+		"Your code is 1234")
+
+	assert.NoError(t, err)
+	assert.False(t, result.Deferred)
+	assert.Equal(t, "queued", result.Response.Status)
+}
+
+func TestQuietHoursSchedulerDefersMessagesUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "Sale!", r.Form.Get("Body"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	s := NewQuietHoursScheduler(NewClient(opts))
+	s.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 7, 59, 59, 950000000, loc)
+	}
+
+	done := make(chan *QuietHoursDeferredSend, 1)
+	s.OnDeferredSend = func(sent *QuietHoursDeferredSend) {
+		done <- sent
+	}
+
+	result, err := s.Send(
+		MessageCategoryMarketing,
+		"+14155552345",
+		"+15108675310",
+		"Sale!")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Deferred)
+	assert.Nil(t, result.Response)
+	assert.Equal(t, time.Date(2019, 6, 1, 8, 0, 0, 0, loc), result.SendAt)
+	assert.Equal(t, 1, s.Pending())
+
+	select {
+	case sent := <-done:
+		assert.NoError(t, sent.Err)
+		assert.Equal(t, MessageCategoryMarketing, sent.Category)
+		assert.Equal(t, "queued", sent.Response.Status)
+	case <-time.After(time.Second):
+		t.Fatal("Deferred message was not sent")
+	}
+
+	assert.Equal(t, 0, s.Pending())
+}
+
+func TestQuietHoursSchedulerStopCancelsDeferredMessages(t *testing.T) {
+	s := NewQuietHoursScheduler(NewClient(NewOptions("sid", "token")))
+	s.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 23, 0, 0, 0, time.UTC)
+	}
+
+	result, err := s.Send(
+		MessageCategoryMarketing,
+		"+447700900123",
+		"+447700900123",
+		"Sale!")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Deferred)
+	assert.Equal(t, 1, s.Pending())
+
+	s.Stop()
+	assert.Equal(t, 0, s.Pending())
+}
+
+func TestQuietHoursSchedulerSchedulesMessagesUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "MGXXXX", r.Form.Get("MessagingServiceSid"))
+		assert.Equal(t, "fixed", r.Form.Get("ScheduleType"))
+		assert.Equal(t, "2019-06-02T15:00:00Z", r.Form.Get("SendAt"))
+		assert.Empty(t, r.Form.Get("From"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "scheduled"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	s := NewQuietHoursScheduler(NewClient(opts))
+	s.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 22, 0, 0, 0, loc)
+	}
+
+	policy := NewQuietHoursPolicy(QuietHoursSchedule)
+	policy.MessagingServiceSID = "MGXXXX"
+	s.SetPolicy(MessageCategoryMarketing, policy)
+
+	result, err := s.Send(
+		MessageCategoryMarketing,
+		"+14155552345",
+		"+15108675310",
+		"Sale!")
+
+	assert.NoError(t, err)
+	assert.False(t, result.Deferred)
+	assert.Equal(t, "scheduled", result.Response.Status)
+}
+
+func TestQuietHoursSchedulerRejectsMessagesUsingLookupFallback(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"country_code": "CA"}`))
+	})
+
+	loc, _ := time.LoadLocation("America/Toronto")
+	opts := NewOptions("sid", "token")
+	opts.LookupBaseURL = server.URL
+	s := NewQuietHoursScheduler(NewClient(opts))
+	s.Now = func() time.Time {
+		return time.Date(2019, 6, 1, 22, 0, 0, 0, loc)
+	}
+	s.SetPolicy(MessageCategoryMarketing, NewQuietHoursPolicy(QuietHoursReject))
+
+	_, err := s.Send(
+		MessageCategoryMarketing,
+		"+14155552345",
+		"+19995552345",
+		"Sale!")
+
+	assert.Equal(t, ErrQuietHours, err)
+}
@@ -0,0 +1,196 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RecordingStatusEvent is a change to the status of a recording, as sent by
+// Twilio to a recording's status callback.
+type RecordingStatusEvent struct {
+	AccountSID      string
+	CallSID         string
+	ConferenceSID   string
+	RecordingSID    string
+	RecordingURL    string
+	RecordingStatus RecordingStatus
+
+	// RecordingSource is how the recording was started, such as
+	// RecordVerb, DialVerb or StartCallRecordingAPI.
+	RecordingSource string
+
+	// RecordingDuration is the length of a completed recording in seconds.
+	RecordingDuration int
+	RecordingChannels int
+
+	// RecordingStartTime is when the recording started.
+	RecordingStartTime time.Time
+
+	// ErrorCode is set when the recording is absent or failed.
+	ErrorCode int
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseRecordingStatusEvent parses the parameters of a recording status
+// callback.
+func ParseRecordingStatusEvent(form url.Values) (*RecordingStatusEvent, error) {
+	event := &RecordingStatusEvent{
+		AccountSID:      form.Get("AccountSid"),
+		CallSID:         form.Get("CallSid"),
+		ConferenceSID:   form.Get("ConferenceSid"),
+		RecordingSID:    form.Get("RecordingSid"),
+		RecordingURL:    form.Get("RecordingUrl"),
+		RecordingStatus: RecordingStatus(form.Get("RecordingStatus")),
+		RecordingSource: form.Get("RecordingSource"),
+		Form:            form,
+	}
+
+	if event.RecordingSID == "" {
+		return nil, fmt.Errorf("Missing parameter: RecordingSid")
+	}
+
+	if event.RecordingStatus == "" {
+		return nil, fmt.Errorf("Missing parameter: RecordingStatus")
+	}
+
+	for _, param := range []struct {
+		key  string
+		dest *int
+	}{
+		{"RecordingDuration", &event.RecordingDuration},
+		{"RecordingChannels", &event.RecordingChannels},
+		{"ErrorCode", &event.ErrorCode},
+	} {
+		var err error
+		*param.dest, err = parseIntParam(form, param.key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if startTime := form.Get("RecordingStartTime"); startTime != "" {
+		var err error
+		event.RecordingStartTime, err = time.Parse(time.RFC1123Z, startTime)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid parameter: RecordingStartTime")
+		}
+	}
+
+	return event, nil
+}
+
+// TranscriptionEvent is the result of transcribing a recording, as sent by
+// Twilio to the transcribe callback of a Record verb.
+type TranscriptionEvent struct {
+	AccountSID          string
+	CallSID             string
+	TranscriptionSID    string
+	TranscriptionText   string
+	TranscriptionStatus TranscriptionStatus
+	TranscriptionURL    string
+	RecordingSID        string
+	RecordingURL        string
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseTranscriptionEvent parses the parameters of a transcribe callback.
+func ParseTranscriptionEvent(form url.Values) (*TranscriptionEvent, error) {
+	event := &TranscriptionEvent{
+		AccountSID:          form.Get("AccountSid"),
+		CallSID:             form.Get("CallSid"),
+		TranscriptionSID:    form.Get("TranscriptionSid"),
+		TranscriptionText:   form.Get("TranscriptionText"),
+		TranscriptionStatus: TranscriptionStatus(form.Get("TranscriptionStatus")),
+		TranscriptionURL:    form.Get("TranscriptionUrl"),
+		RecordingSID:        form.Get("RecordingSid"),
+		RecordingURL:        form.Get("RecordingUrl"),
+		Form:                form,
+	}
+
+	if event.TranscriptionSID == "" {
+		return nil, fmt.Errorf("Missing parameter: TranscriptionSid")
+	}
+
+	if event.TranscriptionStatus == "" {
+		return nil, fmt.Errorf("Missing parameter: TranscriptionStatus")
+	}
+
+	return event, nil
+}
+
+// RecordingStatusHandlerFunc handles a recording status event. When it
+// returns an error, Twilio is asked to retry the callback.
+type RecordingStatusHandlerFunc func(ctx context.Context, event *RecordingStatusEvent) error
+
+// NewRecordingStatusHandler will create a handler for recording status
+// callbacks that validates requests with the validator and handles them with
+// the function. Requests are not validated when the validator is nil. Use a
+// WebhookDeduper to ignore retried callbacks.
+func NewRecordingStatusHandler(
+	validator *RequestValidator,
+	handler RecordingStatusHandlerFunc,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form, ok := validateWebhook(validator, w, r)
+		if !ok {
+			return
+		}
+
+		event, err := ParseRecordingStatusEvent(form)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = handler(r.Context(), event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// TranscriptionHandlerFunc handles a transcription event. When it returns an
+// error, Twilio is asked to retry the callback.
+type TranscriptionHandlerFunc func(ctx context.Context, event *TranscriptionEvent) error
+
+// NewTranscriptionHandler will create a handler for transcribe callbacks
+// that validates requests with the validator and handles them with the
+// function. Requests are not validated when the validator is nil. Use a
+// WebhookDeduper to ignore retried callbacks.
+func NewTranscriptionHandler(
+	validator *RequestValidator,
+	handler TranscriptionHandlerFunc,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form, ok := validateWebhook(validator, w, r)
+		if !ok {
+			return
+		}
+
+		event, err := ParseTranscriptionEvent(form)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = handler(r.Context(), event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
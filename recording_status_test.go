@@ -0,0 +1,108 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecordingStatusEvent(t *testing.T) {
+	event, err := ParseRecordingStatusEvent(url.Values{
+		"CallSid":            {"CA1"},
+		"RecordingSid":       {"RE1"},
+		"RecordingUrl":       {"https://api.twilio.com/Recordings/RE1"},
+		"RecordingStatus":    {"completed"},
+		"RecordingDuration":  {"12"},
+		"RecordingChannels":  {"1"},
+		"RecordingSource":    {"RecordVerb"},
+		"RecordingStartTime": {"Tue, 07 Jan 2020 12:00:00 +0000"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CA1", event.CallSID)
+	assert.Equal(t, RecordingStatusCompleted, event.RecordingStatus)
+	assert.Equal(t, 12, event.RecordingDuration)
+	assert.Equal(t, 1, event.RecordingChannels)
+	assert.Equal(t, "RecordVerb", event.RecordingSource)
+	assert.True(t, time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC).Equal(event.RecordingStartTime))
+
+	_, err = ParseRecordingStatusEvent(url.Values{"RecordingSid": {"RE1"}, "RecordingStatus": {"failed"}, "ErrorCode": {"x"}})
+	assert.EqualError(t, err, "Invalid parameter: ErrorCode")
+
+	_, err = ParseRecordingStatusEvent(url.Values{"RecordingStatus": {"completed"}})
+	assert.EqualError(t, err, "Missing parameter: RecordingSid")
+}
+
+func TestParseTranscriptionEvent(t *testing.T) {
+	event, err := ParseTranscriptionEvent(url.Values{
+		"TranscriptionSid":    {"TR1"},
+		"TranscriptionText":   {"Call me back"},
+		"TranscriptionStatus": {"completed"},
+		"RecordingSid":        {"RE1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Call me back", event.TranscriptionText)
+	assert.Equal(t, TranscriptionStatusCompleted, event.TranscriptionStatus)
+	assert.Equal(t, "RE1", event.RecordingSID)
+
+	_, err = ParseTranscriptionEvent(url.Values{"TranscriptionSid": {"TR1"}})
+	assert.EqualError(t, err, "Missing parameter: TranscriptionStatus")
+}
+
+func TestRecordingStatusHandler(t *testing.T) {
+	var fail bool
+	var events []*RecordingStatusEvent
+	handler := NewRecordingStatusHandler(NewRequestValidator("token"), func(ctx context.Context, event *RecordingStatusEvent) error {
+		if fail {
+			return errors.New("test error")
+		}
+
+		events = append(events, event)
+		return nil
+	})
+
+	send := func(form url.Values) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/recording", form))
+		return w.Code
+	}
+
+	form := url.Values{"RecordingSid": {"RE1"}, "RecordingStatus": {"completed"}}
+	assert.Equal(t, http.StatusNoContent, send(form))
+	assert.Equal(t, http.StatusBadRequest, send(url.Values{"RecordingSid": {"RE1"}}))
+
+	fail = true
+	assert.Equal(t, http.StatusInternalServerError, send(form))
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "RE1", events[0].RecordingSID)
+}
+
+func TestTranscriptionHandler(t *testing.T) {
+	var text string
+	handler := NewTranscriptionHandler(nil, func(ctx context.Context, event *TranscriptionEvent) error {
+		text = event.TranscriptionText
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/transcription", nil)
+	r.PostForm = url.Values{
+		"TranscriptionSid":    {"TR1"},
+		"TranscriptionText":   {"Call me back"},
+		"TranscriptionStatus": {"completed"},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "Call me back", text)
+}
@@ -0,0 +1,237 @@
+package twilio
+
+import (
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordingStatus is the status of a recording.
+type RecordingStatus string
+
+// The statuses a recording can have.
+const (
+	RecordingStatusInProgress RecordingStatus = "in-progress"
+	RecordingStatusPaused     RecordingStatus = "paused"
+	RecordingStatusStopped    RecordingStatus = "stopped"
+	RecordingStatusProcessing RecordingStatus = "processing"
+	RecordingStatusCompleted  RecordingStatus = "completed"
+	RecordingStatusAbsent     RecordingStatus = "absent"
+	RecordingStatusFailed     RecordingStatus = "failed"
+	RecordingStatusDeleted    RecordingStatus = "deleted"
+)
+
+// RecordingFormat is the audio format of a downloaded recording.
+type RecordingFormat string
+
+// The formats a recording can be downloaded in.
+const (
+	RecordingFormatWAV RecordingFormat = "wav"
+	RecordingFormatMP3 RecordingFormat = "mp3"
+)
+
+// CurrentCallRecording can be used as the SID of a call's in-progress
+// recording when pausing, resuming or stopping it.
+const CurrentCallRecording = "Twilio.CURRENT"
+
+// Recording is a recording resource.
+type Recording struct {
+	SID           string          `json:"sid"`
+	AccountSID    string          `json:"account_sid"`
+	CallSID       string          `json:"call_sid"`
+	ConferenceSID string          `json:"conference_sid"`
+	Status        RecordingStatus `json:"status"`
+	Source        string          `json:"source"`
+	Channels      int             `json:"channels"`
+	Track         string          `json:"track"`
+	ErrorCode     int             `json:"error_code"`
+	Price         string          `json:"price"`
+	PriceUnit     string          `json:"price_unit"`
+	MediaURL      string          `json:"media_url"`
+
+	// Duration is the length of the recording in seconds. It is -1 while
+	// the recording is in progress.
+	Duration string `json:"duration"`
+
+	// The dates are in RFC1123Z format.
+	StartTime   string `json:"start_time"`
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// RecordingListParams filters the recordings returned when listing
+// recordings. Empty values are not sent. The times are compared by date
+// only.
+type RecordingListParams struct {
+	CallSID           string
+	ConferenceSID     string
+	DateCreatedAfter  time.Time
+	DateCreatedBefore time.Time
+	PageSize          int
+}
+
+func (params *RecordingListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "CallSid", params.CallSID)
+	setValue(v, "ConferenceSid", params.ConferenceSID)
+	setDate(v, "DateCreated>", params.DateCreatedAfter)
+	setDate(v, "DateCreated<", params.DateCreatedBefore)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+// CallRecordingParams are the parameters for starting to record an
+// in-progress call. Empty values are not sent.
+type CallRecordingParams struct {
+	RecordingChannels             string
+	RecordingTrack                string
+	RecordingStatusCallback       string
+	RecordingStatusCallbackMethod string
+	RecordingStatusCallbackEvents []string
+	Trim                          string
+}
+
+func (params *CallRecordingParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "RecordingChannels", params.RecordingChannels)
+	setValue(v, "RecordingTrack", params.RecordingTrack)
+	setValue(v, "RecordingStatusCallback", params.RecordingStatusCallback)
+	setValue(v, "RecordingStatusCallbackMethod", params.RecordingStatusCallbackMethod)
+	setValues(v, "RecordingStatusCallbackEvent", params.RecordingStatusCallbackEvents)
+	setValue(v, "Trim", params.Trim)
+	return v
+}
+
+type recordingPage struct {
+	Recordings []*Recording `json:"recordings"`
+	apiPageMeta
+}
+
+func (p *recordingPage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// RecordingIterator iterates over a list of recordings, most recent first,
+// fetching pages as needed.
+type RecordingIterator struct {
+	pager   pager
+	items   []*Recording
+	current *Recording
+}
+
+// Next advances to the next recording. It returns false when there are no
+// more recordings or an error occurred.
+func (it *RecordingIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p recordingPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Recordings
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Recording returns the current recording.
+func (it *RecordingIterator) Recording() *Recording {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RecordingIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) FetchRecording(sid string) (*Recording, error) {
+	var recording Recording
+	err := client.fetch(client.accountURL("Recordings", sid), &recording)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recording, nil
+}
+
+func (client *clientImpl) ListRecordings(params *RecordingListParams) *RecordingIterator {
+	return client.listRecordings(client.accountURL("Recordings"), params)
+}
+
+func (client *clientImpl) ListCallRecordings(
+	callSID string,
+	params *RecordingListParams,
+) *RecordingIterator {
+	return client.listRecordings(client.accountURL("Calls", callSID, "Recordings"), params)
+}
+
+func (client *clientImpl) listRecordings(
+	requestURL string,
+	params *RecordingListParams,
+) *RecordingIterator {
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &RecordingIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) DeleteRecording(sid string) error {
+	return client.remove(client.accountURL("Recordings", sid))
+}
+
+func (client *clientImpl) StartCallRecording(
+	callSID string,
+	params *CallRecordingParams,
+) (*Recording, error) {
+	var recording Recording
+	err := client.create(client.accountURL("Calls", callSID, "Recordings"), params.values(), &recording)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recording, nil
+}
+
+func (client *clientImpl) UpdateCallRecording(
+	callSID string,
+	sid string,
+	status RecordingStatus,
+	pauseBehavior string,
+) (*Recording, error) {
+	v := url.Values{}
+	setValue(v, "Status", string(status))
+	setValue(v, "PauseBehavior", pauseBehavior)
+
+	var recording Recording
+	err := client.update(client.accountURL("Calls", callSID, "Recordings", sid), v, &recording)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recording, nil
+}
+
+func (client *clientImpl) DownloadRecording(
+	sid string,
+	format RecordingFormat,
+) (io.ReadCloser, error) {
+	requestURL := strings.TrimSuffix(client.accountURL("Recordings", sid), ".json") + "." + string(format)
+	return client.stream(requestURL)
+}
@@ -0,0 +1,139 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAndDeleteRecordingUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Recordings/RE1.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "RE1", "call_sid": "CA1", "status": "completed", "duration": "12", "channels": 2}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	recording, err := client.FetchRecording("RE1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Recording{
+		SID:      "RE1",
+		CallSID:  "CA1",
+		Status:   RecordingStatusCompleted,
+		Duration: "12",
+		Channels: 2,
+	}, recording)
+
+	assert.NoError(t, client.DeleteRecording("RE1"))
+}
+
+func TestListRecordingsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Recordings.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "CF1", r.URL.Query().Get("ConferenceSid"))
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"recordings": [{"sid": "RE1"}], "next_page_uri": null}`)
+	})
+
+	mux.HandleFunc("/Accounts/sid/Calls/CA1/Recordings.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"recordings": [{"sid": "RE2"}, {"sid": "RE3"}], "next_page_uri": null}`)
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	collect := func(it *RecordingIterator) []string {
+		var sids []string
+		for it.Next() {
+			sids = append(sids, it.Recording().SID)
+		}
+
+		assert.NoError(t, it.Err())
+		return sids
+	}
+
+	assert.Equal(t, []string{"RE1"}, collect(client.ListRecordings(&RecordingListParams{ConferenceSID: "CF1"})))
+	assert.Equal(t, []string{"RE2", "RE3"}, collect(client.ListCallRecordings("CA1", nil)))
+}
+
+func TestCallRecordingControlsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Calls/CA1/Recordings.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{
+			"RecordingChannels":            {"dual"},
+			"RecordingStatusCallback":      {"https://example.com/recording"},
+			"RecordingStatusCallbackEvent": {"in-progress", "completed"},
+		}, r.PostForm)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid": "RE1", "status": "in-progress"}`))
+	})
+
+	mux.HandleFunc("/Accounts/sid/Calls/CA1/Recordings/Twilio.CURRENT.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, url.Values{"Status": {"paused"}, "PauseBehavior": {"silence"}}, r.PostForm)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "RE1", "status": "paused"}`))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	recording, err := client.StartCallRecording("CA1", &CallRecordingParams{
+		RecordingChannels:             "dual",
+		RecordingStatusCallback:       "https://example.com/recording",
+		RecordingStatusCallbackEvents: []string{"in-progress", "completed"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, RecordingStatusInProgress, recording.Status)
+
+	recording, err = client.UpdateCallRecording("CA1", CurrentCallRecording, RecordingStatusPaused, "silence")
+	assert.NoError(t, err)
+	assert.Equal(t, RecordingStatusPaused, recording.Status)
+}
+
+func TestDownloadRecordingUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Recordings/RE1.mp3", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "sid", user)
+		assert.Equal(t, "token", pass)
+
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ID3 audio"))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	body, err := client.DownloadRecording("RE1", RecordingFormatMP3)
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "ID3 audio", string(b))
+
+	_, err = client.DownloadRecording("RE2", RecordingFormatWAV)
+	assert.Equal(t, &UnexpectedResponseError{Expected: http.StatusOK, StatusCode: http.StatusNotFound}, err)
+}
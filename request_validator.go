@@ -0,0 +1,264 @@
+package twilio
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SignatureHeader is the header Twilio uses to sign webhook requests.
+const SignatureHeader = "X-Twilio-Signature"
+
+var (
+	// ErrMissingSignature is returned when a request is not signed.
+	ErrMissingSignature = errors.New("Missing X-Twilio-Signature header")
+
+	// ErrInvalidSignature is returned when a request's signature does not
+	// match.
+	ErrInvalidSignature = errors.New("Invalid X-Twilio-Signature header")
+)
+
+// RequestValidator validates that webhook requests were sent by Twilio.
+type RequestValidator struct {
+	authTokens []string
+
+	// BaseURL, when set, replaces the scheme and host of incoming requests
+	// when reconstructing the URL that Twilio signed. Use it when a proxy
+	// rewrites the scheme, host or port.
+	BaseURL string
+
+	// TrustForwardedHeaders uses the X-Forwarded-Proto and X-Forwarded-Host
+	// headers set by a proxy to reconstruct the URL that Twilio signed.
+	TrustForwardedHeaders bool
+}
+
+// NewRequestValidator will create a validator that accepts requests signed
+// with any of the auth tokens, such as a primary and a secondary token while
+// rotating them. Empty tokens are ignored, so a validator without a token
+// rejects every request.
+func NewRequestValidator(authTokens ...string) *RequestValidator {
+	tokens := make([]string, 0, len(authTokens))
+	for _, token := range authTokens {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return &RequestValidator{
+		authTokens: tokens,
+	}
+}
+
+// NewRequestValidatorWithOptions will create a validator using the auth
+// token from the options. Twilio signs webhooks with the account's auth
+// token, so the AuthToken option must be set when the client authenticates
+// with an API key.
+func NewRequestValidatorWithOptions(opts *Options) *RequestValidator {
+	if opts.AuthToken != "" {
+		return NewRequestValidator(opts.AuthToken)
+	}
+
+	return NewRequestValidator(opts.Token)
+}
+
+// ComputeSignature computes the signature Twilio sends for a request to the
+// URL with the given POST parameters.
+func ComputeSignature(authToken string, requestURL string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(requestURL)
+	for _, key := range keys {
+		values := append([]string{}, params[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			b.WriteString(key)
+			b.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ComputeBodySHA256 computes the value of the bodySHA256 query parameter
+// Twilio adds to webhooks with a JSON body.
+func ComputeBodySHA256(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Validate returns true when the signature matches the URL and POST
+// parameters.
+func (v *RequestValidator) Validate(
+	requestURL string,
+	params url.Values,
+	signature string,
+) bool {
+	for _, candidate := range urlPortVariants(requestURL) {
+		for _, token := range v.authTokens {
+			expected := ComputeSignature(token, candidate, params)
+			if hmac.Equal([]byte(expected), []byte(signature)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidateBody returns true when the signature matches the URL and the
+// body's hash matches the URL's bodySHA256 query parameter.
+func (v *RequestValidator) ValidateBody(
+	requestURL string,
+	body []byte,
+	signature string,
+) bool {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+
+	expected := ComputeBodySHA256(body)
+	actual := u.Query().Get("bodySHA256")
+	if !hmac.Equal([]byte(expected), []byte(actual)) {
+		return false
+	}
+
+	return v.Validate(requestURL, nil, signature)
+}
+
+// ValidateRequest returns an error unless the request was signed by Twilio.
+// Form bodies are parsed into r.PostForm and other bodies are restored so
+// that they can be read again.
+func (v *RequestValidator) ValidateRequest(r *http.Request) error {
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	requestURL := v.URL(r)
+	if r.URL.Query().Get("bodySHA256") != "" {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if !v.ValidateBody(requestURL, body, signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	}
+
+	var params url.Values
+	if r.Method == http.MethodPost {
+		err := r.ParseForm()
+		if err != nil {
+			return err
+		}
+
+		params = r.PostForm
+	}
+
+	if !v.Validate(requestURL, params, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Middleware rejects requests that were not signed by Twilio with a 403
+// Forbidden response.
+func (v *RequestValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.ValidateRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// URL reconstructs the URL that Twilio requested.
+func (v *RequestValidator) URL(r *http.Request) string {
+	if v.BaseURL != "" {
+		return strings.TrimSuffix(v.BaseURL, "/") + r.URL.RequestURI()
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	host := r.Host
+	if v.TrustForwardedHeaders {
+		if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		}
+
+		if forwardedHost := firstHeaderValue(r, "X-Forwarded-Host"); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// firstHeaderValue returns the first of a comma separated list of values
+// added by a chain of proxies.
+func firstHeaderValue(r *http.Request, key string) string {
+	value := r.Header.Get(key)
+	if i := strings.Index(value, ","); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// urlPortVariants returns the URL as given, and with the default port for its
+// scheme removed or added, because Twilio does not consistently include the
+// port when signing.
+func urlPortVariants(requestURL string) []string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return []string{requestURL}
+	}
+
+	defaultPort := map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	if defaultPort == "" {
+		return []string{requestURL}
+	}
+
+	variant := *u
+	host, port, err := net.SplitHostPort(u.Host)
+	switch {
+	case err != nil:
+		variant.Host = net.JoinHostPort(u.Hostname(), defaultPort)
+	case port == defaultPort:
+		variant.Host = host
+	default:
+		return []string{requestURL}
+	}
+
+	return []string{requestURL, variant.String()}
+}
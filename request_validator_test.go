@@ -0,0 +1,145 @@
+// +build unit
+
+package twilio
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSignatureParams = url.Values{
+	"CallSid": {"CA1234567890ABCDE"},
+	"Caller":  {"+12349013030"},
+	"Digits":  {"1234"},
+	"From":    {"+12349013030"},
+	"To":      {"+18005551212"},
+}
+
+const testSignature = "0/KCTR6DLpKmkAf8muzZqo1nDgQ="
+
+func TestComputeSignature(t *testing.T) {
+	signature := ComputeSignature(
+		"12345",
+		"https://mycompany.com/myapp.php?foo=1&bar=2",
+		testSignatureParams)
+
+	assert.Equal(t, testSignature, signature)
+}
+
+func TestRequestValidatorValidate(t *testing.T) {
+	v := NewRequestValidator("12345")
+	requestURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+
+	assert.True(t, v.Validate(requestURL, testSignatureParams, testSignature))
+	assert.True(t, v.Validate(
+		"https://mycompany.com:443/myapp.php?foo=1&bar=2",
+		testSignatureParams,
+		testSignature))
+	assert.False(t, v.Validate(requestURL, url.Values{"Digits": {"1234"}}, testSignature))
+	assert.False(t, NewRequestValidator("54321").Validate(
+		requestURL,
+		testSignatureParams,
+		testSignature))
+
+	v = NewRequestValidator("54321", "12345")
+	assert.True(t, v.Validate(requestURL, testSignatureParams, testSignature))
+}
+
+func TestRequestValidatorIgnoresEmptyTokens(t *testing.T) {
+	requestURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+	signature := ComputeSignature("", requestURL, testSignatureParams)
+
+	v := NewRequestValidator("", "12345")
+	assert.False(t, v.Validate(requestURL, testSignatureParams, signature))
+	assert.True(t, v.Validate(requestURL, testSignatureParams, testSignature))
+
+	v = NewRequestValidatorWithOptions(NewOptions("ACXXXX", ""))
+	assert.False(t, v.Validate(requestURL, testSignatureParams, signature))
+}
+
+func TestRequestValidatorValidateBody(t *testing.T) {
+	v := NewRequestValidator("12345")
+	body := []byte(`{"property": "value", "boolean": true}`)
+	requestURL := "https://mycompany.com/myapp?bodySHA256=" +
+		"0a1ff7634d9ab3b95db5c9a2dfe9416e41502b283a80c7cf19632632f96e6620"
+
+	assert.True(t, v.ValidateBody(requestURL, body, "UEGkzoW/cP+5rgfjjBKC7Wch1b4="))
+	assert.False(t, v.ValidateBody(requestURL, []byte(`{}`), "UEGkzoW/cP+5rgfjjBKC7Wch1b4="))
+}
+
+func TestNewRequestValidatorWithOptions(t *testing.T) {
+	opts := NewOptions("SKXXXX", "secret")
+	opts.AuthToken = "12345"
+	v := NewRequestValidatorWithOptions(opts)
+	requestURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+
+	assert.True(t, v.Validate(requestURL, testSignatureParams, testSignature))
+
+	opts = NewOptions("ACXXXX", "12345")
+	v = NewRequestValidatorWithOptions(opts)
+	assert.True(t, v.Validate(requestURL, testSignatureParams, testSignature))
+}
+
+func TestRequestValidatorMiddleware(t *testing.T) {
+	v := NewRequestValidator("12345")
+	v.TrustForwardedHeaders = true
+
+	handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1234", r.PostForm.Get("Digits"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	newRequest := func(signature string) *http.Request {
+		r := httptest.NewRequest(
+			http.MethodPost,
+			"http://internal:8080/myapp.php?foo=1&bar=2",
+			strings.NewReader(testSignatureParams.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Header.Set("X-Forwarded-Host", "mycompany.com, internal")
+		if signature != "" {
+			r.Header.Set(SignatureHeader, signature)
+		}
+
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest(testSignature))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("forged"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest(""))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "Missing X-Twilio-Signature header\n", w.Body.String())
+}
+
+func TestRequestValidatorValidateRequestWithJSONBody(t *testing.T) {
+	v := NewRequestValidator("12345")
+	v.BaseURL = "https://mycompany.com/"
+
+	body := `{"property": "value", "boolean": true}`
+	r := httptest.NewRequest(
+		http.MethodPost,
+		"http://localhost/myapp?bodySHA256=0a1ff7634d9ab3b95db5c9a2dfe9416e41502b283a80c7cf19632632f96e6620",
+		strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(SignatureHeader, "UEGkzoW/cP+5rgfjjBKC7Wch1b4=")
+
+	assert.NoError(t, v.ValidateRequest(r))
+
+	// The body can still be read by the handler.
+	b, err := ioutil.ReadAll(r.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(b))
+}
@@ -0,0 +1,440 @@
+package twilio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SenderType is the kind of sender used in the From of a message.
+type SenderType string
+
+const (
+	// SenderTypeLongCode is a regular E.164 formatted phone number.
+	SenderTypeLongCode SenderType = "long_code"
+
+	// SenderTypeShortCode is a 3 to 6 digit number provisioned for a single
+	// country.
+	SenderTypeShortCode SenderType = "short_code"
+
+	// SenderTypeAlphanumeric is an alphanumeric sender ID of up to 11
+	// characters, such as a brand name.
+	SenderTypeAlphanumeric SenderType = "alphanumeric"
+)
+
+// SenderIDSupport describes whether a country accepts a type of sender.
+type SenderIDSupport string
+
+const (
+	// SenderIDAllowed means the sender type can be used without registration.
+	SenderIDAllowed SenderIDSupport = "allowed"
+
+	// SenderIDRegistration means only senders that have been registered for
+	// the country can be used.
+	SenderIDRegistration SenderIDSupport = "registration"
+
+	// SenderIDProhibited means the sender type cannot be used.
+	SenderIDProhibited SenderIDSupport = "prohibited"
+)
+
+// SenderIDRule describes the senders that a destination country accepts.
+type SenderIDRule struct {
+	Country      string          `json:"country"`
+	LongCode     SenderIDSupport `json:"long_code"`
+	ShortCode    SenderIDSupport `json:"short_code"`
+	Alphanumeric SenderIDSupport `json:"alphanumeric"`
+
+	// Registered lists the short codes and alphanumeric sender IDs that have
+	// been registered for the country.
+	Registered []string `json:"registered,omitempty"`
+}
+
+// SenderIDError is returned when a sender cannot be used for a destination.
+type SenderIDError struct {
+	From    string
+	To      string
+	Country string
+	Reason  string
+}
+
+func (err *SenderIDError) Error() string {
+	country := err.Country
+	if country == "" {
+		country = "unknown country"
+	}
+
+	if err.From == "" {
+		return fmt.Sprintf("No sender can be used for %s (%s): %s",
+			err.To, country, err.Reason)
+	}
+
+	return fmt.Sprintf("Sender %s cannot be used for %s (%s): %s",
+		err.From, err.To, country, err.Reason)
+}
+
+// defaultSenderIDRulesJSON is the built-in table of sender ID rules. Short
+// codes are provisioned per country, so they always require registration.
+const defaultSenderIDRulesJSON = `[
+	{"country": "US", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "CA", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "MX", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "BR", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "AR", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "CL", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "CO", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "PE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "NZ", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "CN", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"},
+	{"country": "GB", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "IE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "DE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "FR", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "ES", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "IT", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "NL", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "BE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "CH", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "AT", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "SE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "NO", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "DK", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "FI", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "PL", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "PT", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "AU", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "JP", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "ZA", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+	{"country": "IN", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "AE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "SA", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "QA", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "KW", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "EG", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "TR", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "NG", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "KE", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "VN", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "ID", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "PH", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"},
+	{"country": "TH", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration"}
+]`
+
+// SenderIDRules picks and validates senders for destination countries.
+type SenderIDRules struct {
+	mu       sync.RWMutex
+	rules    map[string]*SenderIDRule
+	fallback *SenderIDRule
+}
+
+// NewSenderIDRules will create rules from the built-in table. Countries that
+// are not in the table accept long codes and registered senders only.
+func NewSenderIDRules() *SenderIDRules {
+	rules := &SenderIDRules{
+		rules: map[string]*SenderIDRule{},
+		fallback: &SenderIDRule{
+			LongCode:     SenderIDAllowed,
+			ShortCode:    SenderIDRegistration,
+			Alphanumeric: SenderIDRegistration,
+		},
+	}
+
+	err := rules.Load(strings.NewReader(defaultSenderIDRulesJSON))
+	if err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+// Load reads a JSON array of rules, replacing the rules for any countries it
+// contains. Senders added with Register are kept on the replacing rules. No
+// rules are replaced when any of them is invalid.
+func (r *SenderIDRules) Load(reader io.Reader) error {
+	var rules []*SenderIDRule
+	err := json.NewDecoder(reader).Decode(&rules)
+	if err != nil {
+		return err
+	}
+
+	for i, rule := range rules {
+		err = validateSenderIDRule(rule)
+		if err != nil {
+			return fmt.Errorf("Invalid sender ID rule %d: %s", i, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rule := range rules {
+		country := strings.ToUpper(rule.Country)
+		if existing, ok := r.rules[country]; ok {
+			for _, senderID := range existing.Registered {
+				if !containsSenderID(rule.Registered, senderID) {
+					rule.Registered = append(rule.Registered, senderID)
+				}
+			}
+		}
+
+		r.rules[country] = rule
+	}
+
+	return nil
+}
+
+func containsSenderID(senderIDs []string, senderID string) bool {
+	for _, s := range senderIDs {
+		if s == senderID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateSenderIDRule(rule *SenderIDRule) error {
+	if rule == nil {
+		return errors.New("Rule is null")
+	}
+
+	if rule.Country == "" {
+		return errors.New("Missing country")
+	}
+
+	supports := []struct {
+		name    string
+		support SenderIDSupport
+	}{
+		{"long_code", rule.LongCode},
+		{"short_code", rule.ShortCode},
+		{"alphanumeric", rule.Alphanumeric},
+	}
+
+	for _, s := range supports {
+		switch s.support {
+		case SenderIDAllowed, SenderIDRegistration, SenderIDProhibited:
+		case "":
+			return fmt.Errorf("Missing %s", s.name)
+		default:
+			return fmt.Errorf("Unknown %s support: %s", s.name, s.support)
+		}
+	}
+
+	return nil
+}
+
+// SetRule sets the rule for the rule's country.
+func (r *SenderIDRules) SetRule(rule *SenderIDRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules[strings.ToUpper(rule.Country)] = rule
+}
+
+// SetFallback sets the rule for countries without a rule.
+func (r *SenderIDRules) SetFallback(rule *SenderIDRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = rule
+}
+
+// Rule returns the rule for a country.
+func (r *SenderIDRules) Rule(country string) *SenderIDRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, ok := r.rules[strings.ToUpper(country)]
+	if !ok {
+		return r.fallback
+	}
+
+	return rule
+}
+
+// Register records that a short code or alphanumeric sender ID has been
+// registered for a country.
+func (r *SenderIDRules) Register(country string, senderID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	country = strings.ToUpper(country)
+	rule, ok := r.rules[country]
+	if !ok {
+		copied := *r.fallback
+		copied.Registered = nil
+		rule = &copied
+		rule.Country = country
+	} else {
+		copied := *rule
+		copied.Registered = append([]string{}, rule.Registered...)
+		rule = &copied
+	}
+
+	rule.Registered = append(rule.Registered, senderID)
+	r.rules[country] = rule
+}
+
+// Check returns an error when the sender cannot be used to send a message to
+// the destination phone number. The fallback rule is used when the phone
+// number's country is not known.
+func (r *SenderIDRules) Check(from string, to string) error {
+	country, rule, err := r.destination(to)
+	if err != nil {
+		return err
+	}
+
+	return r.check(from, to, country, rule)
+}
+
+// Choose returns the first candidate sender that can be used to send a
+// message to the destination phone number. The fallback rule is used when
+// the phone number's country is not known.
+func (r *SenderIDRules) Choose(to string, candidates ...string) (string, error) {
+	country, rule, err := r.destination(to)
+	if err != nil {
+		return "", err
+	}
+
+	for _, from := range candidates {
+		if r.check(from, to, country, rule) == nil {
+			return from, nil
+		}
+	}
+
+	return "", &SenderIDError{
+		To:      to,
+		Country: country,
+		Reason:  "none of the candidates are allowed",
+	}
+}
+
+// destination returns the country of a destination phone number and its
+// rule. The country is empty and the fallback rule is returned when the
+// country cannot be resolved.
+func (r *SenderIDRules) destination(to string) (string, *SenderIDRule, error) {
+	digits := strings.TrimPrefix(to, "+")
+	if digits == to || len(digits) < 4 || !isDigits(digits) {
+		return "", nil, fmt.Errorf("Phone number is not in E.164 format: %s", to)
+	}
+
+	country, err := CountryForPhoneNumber(to)
+	if err != nil {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		return "", r.fallback, nil
+	}
+
+	return country, r.Rule(country), nil
+}
+
+func (r *SenderIDRules) check(
+	from string,
+	to string,
+	country string,
+	rule *SenderIDRule,
+) error {
+	senderType := SenderTypeOf(from)
+
+	var support SenderIDSupport
+	switch senderType {
+	case SenderTypeLongCode:
+		support = rule.LongCode
+	case SenderTypeShortCode:
+		support = rule.ShortCode
+	case SenderTypeAlphanumeric:
+		support = rule.Alphanumeric
+	default:
+		return &SenderIDError{
+			From:    from,
+			To:      to,
+			Country: country,
+			Reason:  "not a valid sender",
+		}
+	}
+
+	switch support {
+	case SenderIDAllowed:
+		return nil
+
+	case SenderIDRegistration:
+		for _, registered := range rule.Registered {
+			if strings.EqualFold(registered, from) {
+				return nil
+			}
+		}
+
+		return &SenderIDError{
+			From:    from,
+			To:      to,
+			Country: country,
+			Reason:  fmt.Sprintf("%s senders must be registered", senderType),
+		}
+
+	default:
+		return &SenderIDError{
+			From:    from,
+			To:      to,
+			Country: country,
+			Reason:  fmt.Sprintf("%s senders are prohibited", senderType),
+		}
+	}
+}
+
+// SenderTypeOf returns the type of a sender or an empty string when the
+// sender is not valid.
+func SenderTypeOf(from string) SenderType {
+	if strings.HasPrefix(from, "+") {
+		if len(from) > 1 && isDigits(from[1:]) {
+			return SenderTypeLongCode
+		}
+
+		return ""
+	}
+
+	if isDigits(from) && len(from) >= 3 && len(from) <= 6 {
+		return SenderTypeShortCode
+	}
+
+	if len(from) == 0 || len(from) > 11 {
+		return ""
+	}
+
+	hasLetter := false
+	for _, c := range from {
+		switch {
+		case c > unicode.MaxASCII:
+			return ""
+		case unicode.IsLetter(c):
+			hasLetter = true
+		case unicode.IsDigit(c), c == ' ', c == '-', c == '.', c == '&':
+		default:
+			return ""
+		}
+	}
+
+	if !hasLetter {
+		return ""
+	}
+
+	return SenderTypeAlphanumeric
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
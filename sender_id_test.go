@@ -0,0 +1,163 @@
+// +build unit
+
+package twilio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderTypeOf(t *testing.T) {
+	assert.Equal(t, SenderTypeLongCode, SenderTypeOf("+14155552345"))
+	assert.Equal(t, SenderTypeShortCode, SenderTypeOf("12345"))
+	assert.Equal(t, SenderTypeAlphanumeric, SenderTypeOf("Acme Inc"))
+	assert.Equal(t, SenderType(""), SenderTypeOf("+1415abc"))
+	assert.Equal(t, SenderType(""), SenderTypeOf("TwelveLetter"))
+	assert.Equal(t, SenderType(""), SenderTypeOf("1234567"))
+	assert.Equal(t, SenderType(""), SenderTypeOf(""))
+}
+
+func TestCountryForPhoneNumber(t *testing.T) {
+	country, err := CountryForPhoneNumber("+15108675310")
+	assert.NoError(t, err)
+	assert.Equal(t, "US", country)
+
+	country, err = CountryForPhoneNumber("+14165550123")
+	assert.NoError(t, err)
+	assert.Equal(t, "CA", country)
+
+	country, err = CountryForPhoneNumber("+18765551234")
+	assert.NoError(t, err)
+	assert.Equal(t, "JM", country)
+
+	country, err = CountryForPhoneNumber("+18295551234")
+	assert.NoError(t, err)
+	assert.Equal(t, "DO", country)
+
+	country, err = CountryForPhoneNumber("+12425551234")
+	assert.NoError(t, err)
+	assert.Equal(t, "BS", country)
+
+	country, err = CountryForPhoneNumber("+919812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, "IN", country)
+
+	_, err = CountryForPhoneNumber("+999123456")
+	assert.Error(t, err)
+}
+
+func TestSenderIDRulesCheck(t *testing.T) {
+	rules := NewSenderIDRules()
+
+	assert.NoError(t, rules.Check("+14155552345", "+15108675310"))
+	assert.NoError(t, rules.Check("Acme", "+447700900123"))
+
+	err := rules.Check("Acme", "+15108675310")
+	assert.Equal(t,
+		"Sender Acme cannot be used for +15108675310 (US): alphanumeric senders are prohibited",
+		err.Error())
+
+	err = rules.Check("Acme", "+919812345678")
+	assert.Equal(t,
+		"Sender Acme cannot be used for +919812345678 (IN): alphanumeric senders must be registered",
+		err.Error())
+
+	rules.Register("IN", "ACME")
+	assert.NoError(t, rules.Check("Acme", "+919812345678"))
+	assert.Error(t, rules.Check("12345", "+919812345678"))
+
+	assert.Error(t, rules.Check("not valid!", "+15108675310"))
+	assert.Error(t, rules.Check("+14155552345", "5108675310"))
+}
+
+func TestSenderIDRulesFallbackForUnknownCountry(t *testing.T) {
+	rules := NewSenderIDRules()
+
+	assert.NoError(t, rules.Check("+14155552345", "+263771234567"))
+
+	err := rules.Check("Acme", "+263771234567")
+	assert.Equal(t,
+		"Sender Acme cannot be used for +263771234567 (unknown country): alphanumeric senders must be registered",
+		err.Error())
+
+	from, err := rules.Choose("+263771234567", "Acme", "+14155552345")
+	assert.NoError(t, err)
+	assert.Equal(t, "+14155552345", from)
+}
+
+func TestSenderIDRulesChoose(t *testing.T) {
+	rules := NewSenderIDRules()
+	rules.Register("US", "12345")
+
+	from, err := rules.Choose("+15108675310", "Acme", "12345", "+14155552345")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", from)
+
+	from, err = rules.Choose("+447700900123", "Acme", "12345", "+14155552345")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", from)
+
+	_, err = rules.Choose("+15108675310", "Acme")
+	assert.Equal(t,
+		"No sender can be used for +15108675310 (US): none of the candidates are allowed",
+		err.Error())
+}
+
+func TestSenderIDRulesOverrides(t *testing.T) {
+	rules := NewSenderIDRules()
+
+	err := rules.Load(strings.NewReader(`[
+		{"country": "GB", "long_code": "allowed", "short_code": "registration", "alphanumeric": "prohibited"}
+	]`))
+	assert.NoError(t, err)
+	assert.Error(t, rules.Check("Acme", "+447700900123"))
+
+	err = rules.Load(strings.NewReader(`invalid JSON`))
+	assert.Error(t, err)
+
+	err = rules.Load(strings.NewReader(`[null]`))
+	assert.Equal(t, "Invalid sender ID rule 0: Rule is null", err.Error())
+
+	err = rules.Load(strings.NewReader(`[
+		{"country": "GB", "long_code": "allowed", "short_code": "registration", "alphanumeric": "allowed"},
+		{"country": "FR", "long_code": "allowed", "short_code": "registration"}
+	]`))
+	assert.Equal(t, "Invalid sender ID rule 1: Missing alphanumeric", err.Error())
+	assert.Error(t, rules.Check("Acme", "+447700900123"))
+
+	err = rules.Load(strings.NewReader(`[
+		{"country": "FR", "long_code": "yes", "short_code": "registration", "alphanumeric": "allowed"}
+	]`))
+	assert.Equal(t, "Invalid sender ID rule 0: Unknown long_code support: yes", err.Error())
+
+	assert.Error(t, rules.Check("Acme", "+6512345678"))
+	rules.SetFallback(&SenderIDRule{
+		LongCode:     SenderIDAllowed,
+		ShortCode:    SenderIDProhibited,
+		Alphanumeric: SenderIDAllowed,
+	})
+	assert.NoError(t, rules.Check("Acme", "+6512345678"))
+
+	rules.SetRule(&SenderIDRule{
+		Country:      "sg",
+		LongCode:     SenderIDProhibited,
+		ShortCode:    SenderIDProhibited,
+		Alphanumeric: SenderIDProhibited,
+	})
+	assert.Error(t, rules.Check("+14155552345", "+6512345678"))
+}
+
+func TestSenderIDRulesLoadKeepsRegisteredSenders(t *testing.T) {
+	rules := NewSenderIDRules()
+	rules.Register("IN", "ACME")
+
+	err := rules.Load(strings.NewReader(`[
+		{"country": "IN", "long_code": "allowed", "short_code": "registration", "alphanumeric": "registration", "registered": ["OTHER", "ACME"]}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OTHER", "ACME"}, rules.Rule("IN").Registered)
+	assert.NoError(t, rules.Check("Acme", "+919812345678"))
+	assert.NoError(t, rules.Check("Other", "+919812345678"))
+}
@@ -0,0 +1,111 @@
+package twilio
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SMSListMessagesParams filters the messages returned when listing messages.
+// Empty values are not sent.
+type SMSListMessagesParams struct {
+	From     string
+	To       string
+	DateSent time.Time
+	PageSize int
+}
+
+func (params *SMSListMessagesParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setValue(v, "From", params.From)
+	setValue(v, "To", params.To)
+	setDate(v, "DateSent", params.DateSent)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+type smsMessagePage struct {
+	Messages []*SMSSendMessageResponse `json:"messages"`
+	apiPageMeta
+}
+
+func (p *smsMessagePage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// SMSMessageIterator iterates over a list of messages, most recent first,
+// fetching pages as needed.
+type SMSMessageIterator struct {
+	pager   pager
+	items   []*SMSSendMessageResponse
+	current *SMSSendMessageResponse
+}
+
+// Next advances to the next message. It returns false when there are no more
+// messages or an error occurred.
+func (it *SMSMessageIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p smsMessagePage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Messages
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Message returns the current message.
+func (it *SMSMessageIterator) Message() *SMSSendMessageResponse {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SMSMessageIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) accountURL(path ...string) string {
+	u := client.opts.APIBaseURL + "/Accounts/" + url.PathEscape(client.opts.SID)
+	for i, p := range path {
+		if i == len(path)-1 {
+			p += ".json"
+		}
+
+		u += "/" + url.PathEscape(p)
+	}
+
+	return u
+}
+
+func (client *clientImpl) FetchSMSMessage(sid string) (*SMSSendMessageResponse, error) {
+	var response SMSSendMessageResponse
+	err := client.fetch(client.accountURL("Messages", sid), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (client *clientImpl) ListSMSMessages(
+	params *SMSListMessagesParams,
+) *SMSMessageIterator {
+	requestURL := client.accountURL("Messages")
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &SMSMessageIterator{
+		pager: newPager(client, requestURL),
+	}
+}
@@ -0,0 +1,70 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchSMSMessageUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Messages/SM1.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sid": "SM1", "status": "delivered"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	resp, err := NewClient(opts).FetchSMSMessage("SM1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &SMSSendMessageResponse{
+		SID:    "SM1",
+		Status: "delivered",
+	}, resp)
+}
+
+func TestListSMSMessagesUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Messages.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "+15108675310", r.URL.Query().Get("To"))
+		assert.Equal(t, "2019-06-01", r.URL.Query().Get("DateSent"))
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("Page") == "1" {
+			fmt.Fprint(w, `{"messages": [{"sid": "SM3"}], "next_page_uri": null}`)
+			return
+		}
+
+		fmt.Fprint(w, `{
+	"messages": [{"sid": "SM1"}, {"sid": "SM2"}],
+	"next_page_uri": "/Accounts/sid/Messages.json?To=%2B15108675310&DateSent=2019-06-01&Page=1"
+}`)
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	it := NewClient(opts).ListSMSMessages(&SMSListMessagesParams{
+		To:       "+15108675310",
+		DateSent: time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	var sids []string
+	for it.Next() {
+		sids = append(sids, it.Message().SID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"SM1", "SM2", "SM3"}, sids)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // SMSSendMessageBuilder builds an SMS message to send.
@@ -15,7 +16,13 @@ type SMSSendMessageBuilder struct {
 }
 
 // SMSSendMessageResponse is Twilio's response after sending an SMS message.
+// It is also the representation of messages that are fetched or listed.
 type SMSSendMessageResponse struct {
+	SID          string `json:"sid"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Body         string `json:"body"`
+	DateCreated  string `json:"date_created"`
 	Status       string `json:"status"`
 	ErrorCode    int    `json:"error_code"`
 	ErrorMessage string `json:"error_message"`
@@ -26,6 +33,35 @@ func (client *clientImpl) SendSMSMessage(
 	from string,
 	to string,
 	body string,
+) (*SMSSendMessageResponse, error) {
+	v := url.Values{}
+	v.Set("From", from)
+	v.Set("To", to)
+	v.Set("Body", body)
+
+	return client.sendSMSMessage(v)
+}
+
+// ScheduleSMSMessage asks Twilio to send a message at a fixed time in the
+// future. Twilio only supports scheduling through a messaging service.
+func (client *clientImpl) ScheduleSMSMessage(
+	messagingServiceSID string,
+	to string,
+	body string,
+	sendAt time.Time,
+) (*SMSSendMessageResponse, error) {
+	v := url.Values{}
+	v.Set("MessagingServiceSid", messagingServiceSID)
+	v.Set("To", to)
+	v.Set("Body", body)
+	v.Set("ScheduleType", "fixed")
+	v.Set("SendAt", sendAt.UTC().Format(time.RFC3339))
+
+	return client.sendSMSMessage(v)
+}
+
+func (client *clientImpl) sendSMSMessage(
+	v url.Values,
 ) (*SMSSendMessageResponse, error) {
 	requestURL, err := url.Parse(
 		client.opts.APIBaseURL + "/Accounts/" + client.opts.SID + "/Messages.json")
@@ -33,10 +69,6 @@ func (client *clientImpl) SendSMSMessage(
 		return nil, err
 	}
 
-	v := url.Values{}
-	v.Set("From", from)
-	v.Set("To", to)
-	v.Set("Body", body)
 	rb := *strings.NewReader(v.Encode())
 
 	req, err := http.NewRequest(http.MethodPost, requestURL.String(), &rb)
@@ -170,6 +170,11 @@ func TestSMSSendMessageUsingMockServer(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, &SMSSendMessageResponse{
+		SID:          "MMXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX",
+		From:         "+14155552345",
+		To:           "+15108675310",
+		Body:         "Hello!",
+		DateCreated:  "Thu, 30 Jul 2015 20:12:31 +0000",
 		ErrorMessage: "",
 		ErrorCode:    0,
 		Status:       "sent",
@@ -0,0 +1,296 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeremybower/go-twilio/twiml"
+)
+
+// SMSSessionEnd is the state returned by a state handler to end a session.
+const SMSSessionEnd = ""
+
+// SMSSession is the state of a conversation with a phone number. From is the
+// other party's number and To is the account's number, as they appear in
+// incoming messages.
+type SMSSession struct {
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	State     string            `json:"state"`
+	Data      map[string]string `json:"data,omitempty"`
+	StartedAt time.Time         `json:"started_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// SMSSessionStore persists sessions.
+type SMSSessionStore interface {
+	// Get returns the session for the numbers or nil when there is none.
+	Get(from, to string) (*SMSSession, error)
+
+	// Put inserts or replaces a session.
+	Put(session *SMSSession) error
+
+	// Delete removes the session for the numbers.
+	Delete(from, to string) error
+}
+
+// MemorySMSSessionStore keeps sessions in memory. It is useful for tests and
+// for processes that do not need to survive a restart.
+type MemorySMSSessionStore struct {
+	mu       sync.Mutex
+	sessions map[[2]string]*SMSSession
+}
+
+// NewMemorySMSSessionStore will create an empty in-memory store.
+func NewMemorySMSSessionStore() *MemorySMSSessionStore {
+	return &MemorySMSSessionStore{
+		sessions: map[[2]string]*SMSSession{},
+	}
+}
+
+// Get returns the session for the numbers or nil when there is none.
+func (store *MemorySMSSessionStore) Get(from, to string) (*SMSSession, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	session, ok := store.sessions[[2]string{from, to}]
+	if !ok {
+		return nil, nil
+	}
+
+	return session.copy(), nil
+}
+
+// Put inserts or replaces a session.
+func (store *MemorySMSSessionStore) Put(session *SMSSession) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.sessions[[2]string{session.From, session.To}] = session.copy()
+	return nil
+}
+
+// Delete removes the session for the numbers.
+func (store *MemorySMSSessionStore) Delete(from, to string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.sessions, [2]string{from, to})
+	return nil
+}
+
+func (session *SMSSession) copy() *SMSSession {
+	copied := *session
+	copied.Data = make(map[string]string, len(session.Data))
+	for k, v := range session.Data {
+		copied.Data[k] = v
+	}
+
+	return &copied
+}
+
+// SMSStateHandler handles an incoming message in a state. It returns the
+// reply and the next state, or SMSSessionEnd to end the session. Changes to
+// the session's Data are saved.
+type SMSStateHandler func(
+	ctx context.Context,
+	session *SMSSession,
+	msg *IncomingMessage,
+) (reply *twiml.MessagingResponse, next string, err error)
+
+// SMSSessions runs multi-step conversations, such as surveys and signups,
+// as a state machine. Each incoming message is handled by the state of the
+// session with its sender. Sessions expire when they are idle for longer
+// than the TTL.
+type SMSSessions struct {
+	client Client
+	store  SMSSessionStore
+
+	// Initial is the state of new sessions started by incoming messages.
+	// When it is empty, incoming messages do not start sessions.
+	Initial string
+
+	// TTL is how long a session lasts without an incoming message.
+	TTL time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	mu     sync.RWMutex
+	states map[string]SMSStateHandler
+	locks  keyLocks
+}
+
+// NewSMSSessions will create a state machine that stores sessions in the
+// store and sends proactive messages with the client.
+func NewSMSSessions(client Client, store SMSSessionStore) *SMSSessions {
+	return &SMSSessions{
+		client: client,
+		store:  store,
+		TTL:    30 * time.Minute,
+		Now:    time.Now,
+		states: map[string]SMSStateHandler{},
+	}
+}
+
+// State registers the handler for a state.
+func (s *SMSSessions) State(name string, handler SMSStateHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[name] = handler
+}
+
+// Active returns the unexpired session with the number, or nil when there is
+// none.
+func (s *SMSSessions) Active(from, to string) (*SMSSession, error) {
+	session, err := s.store.Get(from, to)
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	if !s.Now().Before(session.ExpiresAt) {
+		return nil, s.store.Delete(from, to)
+	}
+
+	return session, nil
+}
+
+// Begin sends a message from one of the account's numbers and starts a
+// session in the state, so that the reply is handled by the state.
+func (s *SMSSessions) Begin(from, to, body, state string) (*SMSSession, error) {
+	defer s.lock(to, from)()
+
+	_, err := s.client.SendSMSMessage(from, to, body)
+	if err != nil {
+		return nil, err
+	}
+
+	session := s.newSession(to, from, state)
+	err = s.store.Put(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// End ends the session with the number.
+func (s *SMSSessions) End(from, to string) error {
+	defer s.lock(from, to)()
+
+	return s.store.Delete(from, to)
+}
+
+// Handle handles an incoming message in the state of its session. It has the
+// signature of an IncomingMessageHandlerFunc. An empty response is returned
+// when there is no session and Initial is empty.
+func (s *SMSSessions) Handle(
+	ctx context.Context,
+	msg *IncomingMessage,
+) (*twiml.MessagingResponse, error) {
+	defer s.lock(msg.From, msg.To)()
+
+	session, err := s.Active(msg.From, msg.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		if s.Initial == "" {
+			return nil, nil
+		}
+
+		session = s.newSession(msg.From, msg.To, s.Initial)
+	}
+
+	s.mu.RLock()
+	handler, ok := s.states[session.State]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown session state: %s", session.State)
+	}
+
+	reply, next, err := handler(ctx, session, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if next == SMSSessionEnd {
+		err = s.store.Delete(msg.From, msg.To)
+	} else {
+		session.State = next
+		session.ExpiresAt = s.Now().Add(s.TTL)
+		err = s.store.Put(session)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Start starts a session in the state without sending a message, such as
+// from a keyword route that replies with the first question itself.
+func (s *SMSSessions) Start(from, to, state string) (*SMSSession, error) {
+	defer s.lock(from, to)()
+
+	session := s.newSession(from, to, state)
+	err := s.store.Put(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Middleware handles messages from numbers with an active session, passing
+// other messages to the next handler. Use it with a MessageRouter so that
+// keywords start sessions and replies continue them. Compliance keywords are
+// passed to the next handler, and opt-outs end the session.
+func (s *SMSSessions) Middleware(next IncomingMessageHandlerFunc) IncomingMessageHandlerFunc {
+	return func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+		if match := MessageRouteMatchFromContext(ctx); match != nil && match.Compliance != "" {
+			if match.Compliance == ComplianceOptOut {
+				err := s.End(msg.From, msg.To)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return next(ctx, msg)
+		}
+
+		session, err := s.Active(msg.From, msg.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil {
+			return next(ctx, msg)
+		}
+
+		return s.Handle(ctx, msg)
+	}
+}
+
+func (s *SMSSessions) newSession(from, to, state string) *SMSSession {
+	now := s.Now()
+	return &SMSSession{
+		From:      from,
+		To:        to,
+		State:     state,
+		Data:      map[string]string{},
+		StartedAt: now,
+		ExpiresAt: now.Add(s.TTL),
+	}
+}
+
+// lock serializes the handling of messages in a session, so that messages
+// that arrive together are handled in turn. It returns the unlock function.
+func (s *SMSSessions) lock(from, to string) func() {
+	return s.locks.lock(from + " " + to)
+}
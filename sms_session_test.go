@@ -0,0 +1,132 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jeremybower/go-twilio/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignupSessions(client Client) *SMSSessions {
+	sessions := NewSMSSessions(client, NewMemorySMSSessionStore())
+	sessions.State("name", func(ctx context.Context, session *SMSSession, msg *IncomingMessage) (*twiml.MessagingResponse, string, error) {
+		session.Data["name"] = msg.Body
+		r := twiml.NewMessagingResponse()
+		r.Message("Thanks " + msg.Body + ", what is your email?")
+		return r, "email", nil
+	})
+	sessions.State("email", func(ctx context.Context, session *SMSSession, msg *IncomingMessage) (*twiml.MessagingResponse, string, error) {
+		r := twiml.NewMessagingResponse()
+		r.Message("Signed up " + session.Data["name"] + " <" + msg.Body + ">")
+		return r, SMSSessionEnd, nil
+	})
+
+	return sessions
+}
+
+func sessionReply(t *testing.T, handler IncomingMessageHandlerFunc, body string) string {
+	resp, err := handler(context.Background(), &IncomingMessage{
+		MessageSID: "MM1",
+		From:       "+15108675310",
+		To:         "+14155552345",
+		Body:       body,
+	})
+	assert.NoError(t, err)
+
+	if resp == nil || len(resp.Verbs) == 0 {
+		return ""
+	}
+
+	return resp.Verbs[0].(*twiml.Message).Body.Text
+}
+
+func TestSMSSessionsStateMachine(t *testing.T) {
+	sessions := newSignupSessions(nil)
+	sessions.Initial = "name"
+
+	assert.Equal(t, "Thanks Ada, what is your email?", sessionReply(t, sessions.Handle, "Ada"))
+
+	session, err := sessions.Active("+15108675310", "+14155552345")
+	assert.NoError(t, err)
+	assert.Equal(t, "email", session.State)
+	assert.Equal(t, map[string]string{"name": "Ada"}, session.Data)
+
+	assert.Equal(t, "Signed up Ada <ada@example.com>", sessionReply(t, sessions.Handle, "ada@example.com"))
+
+	session, err = sessions.Active("+15108675310", "+14155552345")
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestSMSSessionsExpire(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	sessions := newSignupSessions(nil)
+	sessions.Now = func() time.Time { return now }
+
+	_, err := sessions.Start("+15108675310", "+14155552345", "email")
+	assert.NoError(t, err)
+
+	now = now.Add(sessions.TTL)
+	session, err := sessions.Active("+15108675310", "+14155552345")
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+
+	assert.Equal(t, "", sessionReply(t, sessions.Handle, "ada@example.com"))
+}
+
+func TestSMSSessionsWithRouter(t *testing.T) {
+	sessions := newSignupSessions(nil)
+
+	router := NewMessageRouter()
+	router.Use(sessions.Middleware)
+	router.Keyword("JOIN", func(ctx context.Context, msg *IncomingMessage) (*twiml.MessagingResponse, error) {
+		_, err := sessions.Start(msg.From, msg.To, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		return replyWith("What is your name?")(ctx, msg)
+	})
+	router.Default(replyWith("Text JOIN to sign up."))
+
+	assert.Equal(t, "Text JOIN to sign up.", sessionReply(t, router.Handle, "hello"))
+	assert.Equal(t, "What is your name?", sessionReply(t, router.Handle, "join"))
+	assert.Equal(t, "Thanks Stop Me, what is your email?", sessionReply(t, router.Handle, "Stop Me"))
+
+	assert.Equal(t, "", sessionReply(t, router.Handle, "STOP"))
+	session, err := sessions.Active("+15108675310", "+14155552345")
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestSMSSessionsBeginUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		assert.NoError(t, err)
+		assert.Equal(t, "+14155552345", r.Form.Get("From"))
+		assert.Equal(t, "+15108675310", r.Form.Get("To"))
+		assert.Equal(t, "What is your name?", r.Form.Get("Body"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "queued"}`))
+	})
+
+	opts := NewOptions("sid", "token")
+	opts.APIBaseURL = server.URL
+	sessions := newSignupSessions(NewClient(opts))
+
+	session, err := sessions.Begin("+14155552345", "+15108675310", "What is your name?", "name")
+	assert.NoError(t, err)
+	assert.Equal(t, "+15108675310", session.From)
+	assert.Equal(t, "+14155552345", session.To)
+
+	assert.Equal(t, "Thanks Ada, what is your email?", sessionReply(t, sessions.Handle, "Ada"))
+}
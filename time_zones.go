@@ -0,0 +1,271 @@
+package twilio
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// countryTimeZone associates a country calling code and an ISO 3166-1 alpha-2
+// country code with a representative IANA time zone. Countries that span
+// several time zones use the zone of their most populous region.
+type countryTimeZone struct {
+	callingCode string
+	countryCode string
+	timeZone    string
+}
+
+var countryTimeZones = []countryTimeZone{
+	{"1", "US", "America/New_York"},
+	{"1", "CA", "America/Toronto"},
+	{"7", "RU", "Europe/Moscow"},
+	{"20", "EG", "Africa/Cairo"},
+	{"27", "ZA", "Africa/Johannesburg"},
+	{"30", "GR", "Europe/Athens"},
+	{"31", "NL", "Europe/Amsterdam"},
+	{"32", "BE", "Europe/Brussels"},
+	{"33", "FR", "Europe/Paris"},
+	{"34", "ES", "Europe/Madrid"},
+	{"36", "HU", "Europe/Budapest"},
+	{"39", "IT", "Europe/Rome"},
+	{"40", "RO", "Europe/Bucharest"},
+	{"41", "CH", "Europe/Zurich"},
+	{"43", "AT", "Europe/Vienna"},
+	{"44", "GB", "Europe/London"},
+	{"45", "DK", "Europe/Copenhagen"},
+	{"46", "SE", "Europe/Stockholm"},
+	{"47", "NO", "Europe/Oslo"},
+	{"48", "PL", "Europe/Warsaw"},
+	{"49", "DE", "Europe/Berlin"},
+	{"51", "PE", "America/Lima"},
+	{"52", "MX", "America/Mexico_City"},
+	{"53", "CU", "America/Havana"},
+	{"54", "AR", "America/Argentina/Buenos_Aires"},
+	{"55", "BR", "America/Sao_Paulo"},
+	{"56", "CL", "America/Santiago"},
+	{"57", "CO", "America/Bogota"},
+	{"58", "VE", "America/Caracas"},
+	{"60", "MY", "Asia/Kuala_Lumpur"},
+	{"61", "AU", "Australia/Sydney"},
+	{"62", "ID", "Asia/Jakarta"},
+	{"63", "PH", "Asia/Manila"},
+	{"64", "NZ", "Pacific/Auckland"},
+	{"65", "SG", "Asia/Singapore"},
+	{"66", "TH", "Asia/Bangkok"},
+	{"81", "JP", "Asia/Tokyo"},
+	{"82", "KR", "Asia/Seoul"},
+	{"84", "VN", "Asia/Ho_Chi_Minh"},
+	{"86", "CN", "Asia/Shanghai"},
+	{"90", "TR", "Europe/Istanbul"},
+	{"91", "IN", "Asia/Kolkata"},
+	{"92", "PK", "Asia/Karachi"},
+	{"93", "AF", "Asia/Kabul"},
+	{"94", "LK", "Asia/Colombo"},
+	{"95", "MM", "Asia/Yangon"},
+	{"98", "IR", "Asia/Tehran"},
+	{"212", "MA", "Africa/Casablanca"},
+	{"213", "DZ", "Africa/Algiers"},
+	{"216", "TN", "Africa/Tunis"},
+	{"233", "GH", "Africa/Accra"},
+	{"234", "NG", "Africa/Lagos"},
+	{"254", "KE", "Africa/Nairobi"},
+	{"255", "TZ", "Africa/Dar_es_Salaam"},
+	{"256", "UG", "Africa/Kampala"},
+	{"351", "PT", "Europe/Lisbon"},
+	{"352", "LU", "Europe/Luxembourg"},
+	{"353", "IE", "Europe/Dublin"},
+	{"354", "IS", "Atlantic/Reykjavik"},
+	{"358", "FI", "Europe/Helsinki"},
+	{"359", "BG", "Europe/Sofia"},
+	{"370", "LT", "Europe/Vilnius"},
+	{"371", "LV", "Europe/Riga"},
+	{"372", "EE", "Europe/Tallinn"},
+	{"380", "UA", "Europe/Kiev"},
+	{"385", "HR", "Europe/Zagreb"},
+	{"386", "SI", "Europe/Ljubljana"},
+	{"420", "CZ", "Europe/Prague"},
+	{"421", "SK", "Europe/Bratislava"},
+	{"852", "HK", "Asia/Hong_Kong"},
+	{"880", "BD", "Asia/Dhaka"},
+	{"886", "TW", "Asia/Taipei"},
+	{"965", "KW", "Asia/Kuwait"},
+	{"966", "SA", "Asia/Riyadh"},
+	{"971", "AE", "Asia/Dubai"},
+	{"972", "IL", "Asia/Jerusalem"},
+	{"974", "QA", "Asia/Qatar"},
+}
+
+// nanpTimeZones maps North American Numbering Plan area codes to time
+// zones. Area codes that straddle a time zone boundary use the zone that
+// covers most of their subscribers.
+var nanpTimeZones = map[string]string{
+	"America/New_York":             "201 202 203 207 212 215 216 220 223 229 234 239 240 248 252 267 272 276 283 301 302 304 305 315 321 326 329 330 332 336 339 347 351 352 363 380 386 401 404 407 410 412 413 419 423 434 436 440 443 445 448 470 472 475 478 484 502 508 513 516 517 518 540 551 561 567 570 571 582 585 586 603 606 607 609 610 614 617 624 631 640 646 656 667 678 680 681 689 703 704 706 716 717 718 724 727 732 734 740 743 754 757 762 770 771 772 774 781 786 802 803 804 810 813 814 826 835 838 839 843 845 848 850 854 856 857 859 860 862 863 864 865 878 904 908 910 912 914 917 919 929 934 937 941 943 948 954 959 973 978 980 984",
+	"America/Detroit":              "231 269 313 616 679 906 947 989",
+	"America/Indiana/Indianapolis": "260 317 463 574 765 812 930",
+	"America/Toronto":              "226 249 263 289 343 354 365 367 382 416 418 437 438 450 468 514 519 548 579 581 613 647 683 705 742 753 807 819 873 905",
+	"America/Halifax":              "428 506 782 902",
+	"America/St_Johns":             "709 879",
+	"America/Chicago":              "205 210 214 217 218 224 225 228 251 254 256 262 270 274 281 308 309 312 314 316 318 319 320 325 327 331 334 337 346 361 364 402 405 409 414 417 430 432 447 464 469 479 501 504 507 512 515 531 534 539 557 563 572 573 580 601 605 608 612 615 618 620 629 630 636 641 651 659 660 662 682 701 708 712 713 715 726 730 731 737 763 769 773 779 785 806 815 816 817 830 832 847 861 870 872 901 903 913 918 920 931 936 938 940 945 952 956 972 975 979 985",
+	"America/Winnipeg":             "204 431 584",
+	"America/Regina":               "306 474 639",
+	"America/Denver":               "208 303 307 385 406 435 505 575 719 720 801 915 970 983 986",
+	"America/Phoenix":              "480 520 602 623 928",
+	"America/Edmonton":             "368 403 587 780 825",
+	"America/Los_Angeles":          "206 209 213 253 279 310 323 341 350 360 408 415 424 425 442 458 503 509 510 530 541 559 562 564 619 626 628 650 657 661 669 702 707 714 725 747 760 775 805 818 820 831 840 858 909 916 925 949 951 971",
+	"America/Vancouver":            "236 250 257 604 672 778",
+	"America/Anchorage":            "907",
+	"Pacific/Honolulu":             "808",
+	"America/Puerto_Rico":          "787 939",
+	"America/St_Thomas":            "340",
+	"America/Jamaica":              "658 876",
+	"America/Nassau":               "242",
+	"America/Barbados":             "246",
+	"America/Santo_Domingo":        "809 829 849",
+	"America/Port_of_Spain":        "868",
+	"America/Cayman":               "345",
+	"Atlantic/Bermuda":             "441",
+	"Pacific/Guam":                 "671",
+	"America/Antigua":              "268",
+	"America/Anguilla":             "264",
+	"America/Tortola":              "284",
+	"America/Grenada":              "473",
+	"America/Grand_Turk":           "649",
+	"America/Montserrat":           "664",
+	"Pacific/Saipan":               "670",
+	"Pacific/Pago_Pago":            "684",
+	"America/Lower_Princes":        "721",
+	"America/St_Lucia":             "758",
+	"America/Dominica":             "767",
+	"America/St_Vincent":           "784",
+	"America/St_Kitts":             "869",
+}
+
+// nanpCountries maps the North American Numbering Plan area codes of
+// countries and territories other than the US and Canada to their ISO 3166-1
+// alpha-2 country codes.
+var nanpCountries = map[string]string{
+	"AG": "268",
+	"AI": "264",
+	"AS": "684",
+	"BB": "246",
+	"BM": "441",
+	"BS": "242",
+	"DM": "767",
+	"DO": "809 829 849",
+	"GD": "473",
+	"GU": "671",
+	"JM": "658 876",
+	"KN": "869",
+	"KY": "345",
+	"LC": "758",
+	"MP": "670",
+	"MS": "664",
+	"PR": "787 939",
+	"SX": "721",
+	"TC": "649",
+	"TT": "868",
+	"VC": "784",
+	"VG": "284",
+	"VI": "340",
+}
+
+var nanpAreaCodeTimeZones = byAreaCode(nanpTimeZones)
+
+var nanpAreaCodeCountries = byAreaCode(nanpCountries)
+
+func byAreaCode(values map[string]string) map[string]string {
+	m := map[string]string{}
+	for value, codes := range values {
+		for _, code := range strings.Fields(codes) {
+			m[code] = value
+		}
+	}
+
+	return m
+}
+
+// TimeZoneForPhoneNumber infers the time zone of an E.164 formatted phone
+// number from its country calling code and, within the North American
+// Numbering Plan, its area code.
+func TimeZoneForPhoneNumber(phoneNumber string) (*time.Location, error) {
+	digits := strings.TrimPrefix(phoneNumber, "+")
+	if digits == phoneNumber || len(digits) < 4 {
+		return nil, fmt.Errorf("Phone number is not in E.164 format: %s", phoneNumber)
+	}
+
+	if strings.HasPrefix(digits, "1") {
+		tz, ok := nanpAreaCodeTimeZones[digits[1:4]]
+		if !ok {
+			return nil, fmt.Errorf("Unknown area code: %s", digits[1:4])
+		}
+
+		return time.LoadLocation(tz)
+	}
+
+	for n := 3; n > 0; n-- {
+		for _, ctz := range countryTimeZones {
+			if ctz.callingCode == digits[:n] {
+				return time.LoadLocation(ctz.timeZone)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown country calling code: %s", phoneNumber)
+}
+
+// TimeZoneForCountry returns a representative time zone for an ISO 3166-1
+// alpha-2 country code, such as the one returned by LookupPhoneNumber.
+func TimeZoneForCountry(countryCode string) (*time.Location, error) {
+	countryCode = strings.ToUpper(countryCode)
+	for _, ctz := range countryTimeZones {
+		if ctz.countryCode == countryCode {
+			return time.LoadLocation(ctz.timeZone)
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown country code: %s", countryCode)
+}
+
+// nanpCanadianTimeZones are the time zones whose area codes are Canadian.
+var nanpCanadianTimeZones = map[string]bool{
+	"America/Toronto":   true,
+	"America/Halifax":   true,
+	"America/St_Johns":  true,
+	"America/Winnipeg":  true,
+	"America/Regina":    true,
+	"America/Edmonton":  true,
+	"America/Vancouver": true,
+}
+
+// CountryForPhoneNumber returns the ISO 3166-1 alpha-2 country code for an
+// E.164 formatted phone number. Numbers in the North American Numbering Plan
+// are reported as US unless their area code belongs to Canada or another
+// country.
+func CountryForPhoneNumber(phoneNumber string) (string, error) {
+	digits := strings.TrimPrefix(phoneNumber, "+")
+	if digits == phoneNumber || len(digits) < 4 {
+		return "", fmt.Errorf("Phone number is not in E.164 format: %s", phoneNumber)
+	}
+
+	if strings.HasPrefix(digits, "1") {
+		if country, ok := nanpAreaCodeCountries[digits[1:4]]; ok {
+			return country, nil
+		}
+
+		if nanpCanadianTimeZones[nanpAreaCodeTimeZones[digits[1:4]]] {
+			return "CA", nil
+		}
+
+		return "US", nil
+	}
+
+	for n := 3; n > 0; n-- {
+		for _, ctz := range countryTimeZones {
+			if ctz.callingCode == digits[:n] {
+				return ctz.countryCode, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("Unknown country calling code: %s", phoneNumber)
+}
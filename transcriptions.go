@@ -0,0 +1,148 @@
+package twilio
+
+import (
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TranscriptionStatus is the status of a transcription.
+type TranscriptionStatus string
+
+// The statuses a transcription can have.
+const (
+	TranscriptionStatusInProgress TranscriptionStatus = "in-progress"
+	TranscriptionStatusCompleted  TranscriptionStatus = "completed"
+	TranscriptionStatusFailed     TranscriptionStatus = "failed"
+)
+
+// Transcription is a transcription of a recording.
+type Transcription struct {
+	SID               string              `json:"sid"`
+	AccountSID        string              `json:"account_sid"`
+	RecordingSID      string              `json:"recording_sid"`
+	Status            TranscriptionStatus `json:"status"`
+	TranscriptionText string              `json:"transcription_text"`
+	Type              string              `json:"type"`
+	Price             string              `json:"price"`
+	PriceUnit         string              `json:"price_unit"`
+
+	// Duration is the length of the transcribed recording in seconds.
+	Duration string `json:"duration"`
+
+	// The dates are in RFC1123Z format.
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+}
+
+// TranscriptionListParams filters the transcriptions returned when listing
+// transcriptions. Empty values are not sent. The times are compared by date
+// only.
+type TranscriptionListParams struct {
+	DateCreatedAfter  time.Time
+	DateCreatedBefore time.Time
+	PageSize          int
+}
+
+func (params *TranscriptionListParams) values() url.Values {
+	v := url.Values{}
+	if params == nil {
+		return v
+	}
+
+	setDate(v, "DateCreated>", params.DateCreatedAfter)
+	setDate(v, "DateCreated<", params.DateCreatedBefore)
+	if params.PageSize > 0 {
+		v.Set("PageSize", strconv.Itoa(params.PageSize))
+	}
+
+	return v
+}
+
+type transcriptionPage struct {
+	Transcriptions []*Transcription `json:"transcriptions"`
+	apiPageMeta
+}
+
+func (p *transcriptionPage) nextPageURL() string {
+	return p.NextPageURI
+}
+
+// TranscriptionIterator iterates over a list of transcriptions, most recent
+// first, fetching pages as needed.
+type TranscriptionIterator struct {
+	pager   pager
+	items   []*Transcription
+	current *Transcription
+}
+
+// Next advances to the next transcription. It returns false when there are
+// no more transcriptions or an error occurred.
+func (it *TranscriptionIterator) Next() bool {
+	for len(it.items) == 0 {
+		var p transcriptionPage
+		if !it.pager.fetch(&p) {
+			return false
+		}
+
+		it.items = p.Transcriptions
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Transcription returns the current transcription.
+func (it *TranscriptionIterator) Transcription() *Transcription {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TranscriptionIterator) Err() error {
+	return it.pager.err
+}
+
+func (client *clientImpl) FetchTranscription(sid string) (*Transcription, error) {
+	var transcription Transcription
+	err := client.fetch(client.accountURL("Transcriptions", sid), &transcription)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transcription, nil
+}
+
+func (client *clientImpl) ListTranscriptions(params *TranscriptionListParams) *TranscriptionIterator {
+	return client.listTranscriptions(client.accountURL("Transcriptions"), params)
+}
+
+func (client *clientImpl) ListRecordingTranscriptions(
+	recordingSID string,
+	params *TranscriptionListParams,
+) *TranscriptionIterator {
+	return client.listTranscriptions(client.accountURL("Recordings", recordingSID, "Transcriptions"), params)
+}
+
+func (client *clientImpl) listTranscriptions(
+	requestURL string,
+	params *TranscriptionListParams,
+) *TranscriptionIterator {
+	if v := params.values(); len(v) > 0 {
+		requestURL += "?" + v.Encode()
+	}
+
+	return &TranscriptionIterator{
+		pager: newPager(client, requestURL),
+	}
+}
+
+func (client *clientImpl) DeleteTranscription(sid string) error {
+	return client.remove(client.accountURL("Transcriptions", sid))
+}
+
+func (client *clientImpl) DownloadTranscriptionText(sid string) (io.ReadCloser, error) {
+	requestURL := strings.TrimSuffix(client.accountURL("Transcriptions", sid), ".json") + ".txt"
+	return client.stream(requestURL)
+}
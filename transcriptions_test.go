@@ -0,0 +1,92 @@
+// +build unit
+
+package twilio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAndDeleteTranscriptionUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Transcriptions/TR1.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sid": "TR1", "recording_sid": "RE1", "status": "completed", "transcription_text": "Call me back"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	transcription, err := client.FetchTranscription("TR1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Transcription{
+		SID:               "TR1",
+		RecordingSID:      "RE1",
+		Status:            TranscriptionStatusCompleted,
+		TranscriptionText: "Call me back",
+	}, transcription)
+
+	assert.NoError(t, client.DeleteTranscription("TR1"))
+}
+
+func TestListTranscriptionsUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Transcriptions.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2020-01-07", r.URL.Query().Get("DateCreated>"))
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"transcriptions": [{"sid": "TR1"}], "next_page_uri": null}`)
+	})
+
+	mux.HandleFunc("/Accounts/sid/Recordings/RE1/Transcriptions.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"transcriptions": [{"sid": "TR2"}, {"sid": "TR3"}], "next_page_uri": null}`)
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	collect := func(it *TranscriptionIterator) []string {
+		var sids []string
+		for it.Next() {
+			sids = append(sids, it.Transcription().SID)
+		}
+
+		assert.NoError(t, it.Err())
+		return sids
+	}
+
+	params := &TranscriptionListParams{DateCreatedAfter: time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, []string{"TR1"}, collect(client.ListTranscriptions(params)))
+	assert.Equal(t, []string{"TR2", "TR3"}, collect(client.ListRecordingTranscriptions("RE1", nil)))
+}
+
+func TestDownloadTranscriptionTextUsingMockServer(t *testing.T) {
+	mux, server, shutdown := setupMockServer()
+	defer shutdown()
+
+	mux.HandleFunc("/Accounts/sid/Transcriptions/TR1.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Call me back"))
+	})
+
+	client := newCallsClientUsingMockServer(server.URL)
+	body, err := client.DownloadTranscriptionText("TR1")
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "Call me back", string(b))
+}
@@ -0,0 +1,78 @@
+package twiml
+
+import "encoding/xml"
+
+// ConnectNoun is a noun that can be nested in a Connect.
+type ConnectNoun interface {
+	connectNoun()
+}
+
+// Connect connects the call to another service.
+type Connect struct {
+	XMLName xml.Name `xml:"Connect"`
+	Action  string   `xml:"action,attr,omitempty"`
+	Method  Method   `xml:"method,attr,omitempty"`
+	Nouns   []ConnectNoun
+}
+
+// NewConnect will create an empty Connect.
+func NewConnect() *Connect {
+	return &Connect{}
+}
+
+func (*Connect) voiceVerb() {}
+
+// Append adds nouns to connect to.
+func (c *Connect) Append(nouns ...ConnectNoun) *Connect {
+	c.Nouns = append(c.Nouns, nouns...)
+	return c
+}
+
+// Stream adds a bidirectional media stream to the WebSocket URL.
+func (c *Connect) Stream(url string) *Stream {
+	s := NewStream(url)
+	c.Append(s)
+	return s
+}
+
+// StreamTrack is the audio track sent over a media stream.
+type StreamTrack string
+
+// The audio tracks of a media stream.
+const (
+	StreamTrackInbound  StreamTrack = "inbound_track"
+	StreamTrackOutbound StreamTrack = "outbound_track"
+	StreamTrackBoth     StreamTrack = "both_tracks"
+)
+
+// Stream streams the call's audio to a WebSocket server.
+type Stream struct {
+	XMLName              xml.Name     `xml:"Stream"`
+	URL                  string       `xml:"url,attr"`
+	Name                 string       `xml:"name,attr,omitempty"`
+	Track                StreamTrack  `xml:"track,attr,omitempty"`
+	StatusCallback       string       `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod Method       `xml:"statusCallbackMethod,attr,omitempty"`
+	Parameters           []*Parameter `xml:"Parameter"`
+}
+
+// NewStream will create a Stream to the WebSocket URL.
+func NewStream(url string) *Stream {
+	return &Stream{URL: url}
+}
+
+func (*Stream) connectNoun() {}
+
+// Parameter adds a custom parameter that is sent to the WebSocket server
+// when the stream starts.
+func (s *Stream) Parameter(name, value string) *Stream {
+	s.Parameters = append(s.Parameters, &Parameter{Name: name, Value: value})
+	return s
+}
+
+// Parameter is a custom parameter sent with a media stream.
+type Parameter struct {
+	XMLName xml.Name `xml:"Parameter"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:"value,attr"`
+}
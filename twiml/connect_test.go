@@ -0,0 +1,27 @@
+// +build unit
+
+package twiml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectStreamMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	c := r.Connect()
+	c.Action = "https://example.com/after"
+	s := c.Stream("wss://example.com/stream").Parameter("customer", "42")
+	s.Track = StreamTrackInbound
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Connect action="https://example.com/after">`+
+		`<Stream url="wss://example.com/stream" track="inbound_track">`+
+		`<Parameter name="customer" value="42"></Parameter>`+
+		`</Stream>`+
+		`</Connect>`+
+		`</Response>`, string(b))
+}
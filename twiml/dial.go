@@ -0,0 +1,245 @@
+package twiml
+
+import "encoding/xml"
+
+// DialNoun is a noun that can be nested in a Dial.
+type DialNoun interface {
+	dialNoun()
+}
+
+// DialRecord determines whether a dialed call is recorded.
+type DialRecord string
+
+// The recording options for dialed calls.
+const (
+	DialDoNotRecord           DialRecord = "do-not-record"
+	DialRecordFromAnswer      DialRecord = "record-from-answer"
+	DialRecordFromRinging     DialRecord = "record-from-ringing"
+	DialRecordFromAnswerDual  DialRecord = "record-from-answer-dual"
+	DialRecordFromRingingDual DialRecord = "record-from-ringing-dual"
+)
+
+// Dial connects the caller to another party. The party is either a phone
+// number in Text or one or more nouns.
+type Dial struct {
+	XMLName                       xml.Name   `xml:"Dial"`
+	Action                        string     `xml:"action,attr,omitempty"`
+	Method                        Method     `xml:"method,attr,omitempty"`
+	Timeout                       int        `xml:"timeout,attr,omitempty"`
+	HangupOnStar                  *bool      `xml:"hangupOnStar,attr"`
+	TimeLimit                     int        `xml:"timeLimit,attr,omitempty"`
+	CallerID                      string     `xml:"callerId,attr,omitempty"`
+	Record                        DialRecord `xml:"record,attr,omitempty"`
+	Trim                          RecordTrim `xml:"trim,attr,omitempty"`
+	RecordingStatusCallback       string     `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod Method     `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	AnswerOnBridge                *bool      `xml:"answerOnBridge,attr"`
+	RingTone                      string     `xml:"ringTone,attr,omitempty"`
+	Text                          string     `xml:",chardata"`
+	Nouns                         []DialNoun
+}
+
+// NewDial will create a Dial for the phone number, which may be empty when
+// nouns are added instead.
+func NewDial(number string) *Dial {
+	return &Dial{Text: number}
+}
+
+func (*Dial) voiceVerb() {}
+
+// Append adds nouns to dial. All of them are dialed at once and the first to
+// answer is connected.
+func (d *Dial) Append(nouns ...DialNoun) *Dial {
+	d.Nouns = append(d.Nouns, nouns...)
+	return d
+}
+
+// Number adds a phone number to dial.
+func (d *Dial) Number(number string) *Number {
+	n := NewNumber(number)
+	d.Append(n)
+	return n
+}
+
+// Client adds a client identity to dial.
+func (d *Dial) Client(identity string) *Client {
+	c := NewClient(identity)
+	d.Append(c)
+	return c
+}
+
+// Sip adds a SIP URI to dial.
+func (d *Dial) Sip(uri string) *Sip {
+	s := NewSip(uri)
+	d.Append(s)
+	return s
+}
+
+// Conference adds the named conference to join.
+func (d *Dial) Conference(name string) *Conference {
+	c := NewConference(name)
+	d.Append(c)
+	return c
+}
+
+// Queue adds the named queue to dequeue a caller from.
+func (d *Dial) Queue(name string) *Queue {
+	q := NewQueue(name)
+	d.Append(q)
+	return q
+}
+
+// CallStatusEvent is a call progress event reported to a status callback.
+type CallStatusEvent string
+
+// The call progress events.
+const (
+	CallStatusEventInitiated CallStatusEvent = "initiated"
+	CallStatusEventRinging   CallStatusEvent = "ringing"
+	CallStatusEventAnswered  CallStatusEvent = "answered"
+	CallStatusEventCompleted CallStatusEvent = "completed"
+)
+
+// Number is a phone number to dial.
+type Number struct {
+	XMLName              xml.Name `xml:"Number"`
+	SendDigits           string   `xml:"sendDigits,attr,omitempty"`
+	URL                  string   `xml:"url,attr,omitempty"`
+	Method               Method   `xml:"method,attr,omitempty"`
+	StatusCallbackEvent  string   `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback       string   `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod Method   `xml:"statusCallbackMethod,attr,omitempty"`
+	BYOC                 string   `xml:"byoc,attr,omitempty"`
+	PhoneNumber          string   `xml:",chardata"`
+}
+
+// NewNumber will create a Number.
+func NewNumber(number string) *Number {
+	return &Number{PhoneNumber: number}
+}
+
+func (*Number) dialNoun() {}
+
+// SetStatusCallbackEvents sets the events reported to the status callback.
+func (n *Number) SetStatusCallbackEvents(events ...CallStatusEvent) *Number {
+	n.StatusCallbackEvent = joinEvents(events)
+	return n
+}
+
+// Client is a client identity to dial.
+type Client struct {
+	XMLName              xml.Name `xml:"Client"`
+	URL                  string   `xml:"url,attr,omitempty"`
+	Method               Method   `xml:"method,attr,omitempty"`
+	StatusCallbackEvent  string   `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback       string   `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod Method   `xml:"statusCallbackMethod,attr,omitempty"`
+	Identity             string   `xml:",chardata"`
+}
+
+// NewClient will create a Client.
+func NewClient(identity string) *Client {
+	return &Client{Identity: identity}
+}
+
+func (*Client) dialNoun() {}
+
+// Sip is a SIP URI to dial.
+type Sip struct {
+	XMLName              xml.Name `xml:"Sip"`
+	Username             string   `xml:"username,attr,omitempty"`
+	Password             string   `xml:"password,attr,omitempty"`
+	URL                  string   `xml:"url,attr,omitempty"`
+	Method               Method   `xml:"method,attr,omitempty"`
+	StatusCallbackEvent  string   `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback       string   `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod Method   `xml:"statusCallbackMethod,attr,omitempty"`
+	URI                  string   `xml:",chardata"`
+}
+
+// NewSip will create a Sip.
+func NewSip(uri string) *Sip {
+	return &Sip{URI: uri}
+}
+
+func (*Sip) dialNoun() {}
+
+// ConferenceBeep determines when a beep is played as participants join and
+// leave a conference.
+type ConferenceBeep string
+
+// The beep options for conferences.
+const (
+	ConferenceBeepTrue    ConferenceBeep = "true"
+	ConferenceBeepFalse   ConferenceBeep = "false"
+	ConferenceBeepOnEnter ConferenceBeep = "onEnter"
+	ConferenceBeepOnExit  ConferenceBeep = "onExit"
+)
+
+// ConferenceRecord determines whether a conference is recorded.
+type ConferenceRecord string
+
+// The recording options for conferences.
+const (
+	ConferenceDoNotRecord     ConferenceRecord = "do-not-record"
+	ConferenceRecordFromStart ConferenceRecord = "record-from-start"
+)
+
+// Conference joins the caller to a conference.
+type Conference struct {
+	XMLName                       xml.Name         `xml:"Conference"`
+	Muted                         *bool            `xml:"muted,attr"`
+	Beep                          ConferenceBeep   `xml:"beep,attr,omitempty"`
+	StartConferenceOnEnter        *bool            `xml:"startConferenceOnEnter,attr"`
+	EndConferenceOnExit           *bool            `xml:"endConferenceOnExit,attr"`
+	WaitURL                       string           `xml:"waitUrl,attr,omitempty"`
+	WaitMethod                    Method           `xml:"waitMethod,attr,omitempty"`
+	MaxParticipants               int              `xml:"maxParticipants,attr,omitempty"`
+	Record                        ConferenceRecord `xml:"record,attr,omitempty"`
+	Region                        string           `xml:"region,attr,omitempty"`
+	Coach                         string           `xml:"coach,attr,omitempty"`
+	Trim                          RecordTrim       `xml:"trim,attr,omitempty"`
+	StatusCallbackEvent           string           `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback                string           `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod          Method           `xml:"statusCallbackMethod,attr,omitempty"`
+	RecordingStatusCallback       string           `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod Method           `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	Name                          string           `xml:",chardata"`
+}
+
+// NewConference will create a Conference.
+func NewConference(name string) *Conference {
+	return &Conference{Name: name}
+}
+
+func (*Conference) dialNoun() {}
+
+// Queue dequeues a caller from a queue and connects them.
+type Queue struct {
+	XMLName             xml.Name `xml:"Queue"`
+	URL                 string   `xml:"url,attr,omitempty"`
+	Method              Method   `xml:"method,attr,omitempty"`
+	ReservationSID      string   `xml:"reservationSid,attr,omitempty"`
+	PostWorkActivitySID string   `xml:"postWorkActivitySid,attr,omitempty"`
+	Name                string   `xml:",chardata"`
+}
+
+// NewQueue will create a Queue.
+func NewQueue(name string) *Queue {
+	return &Queue{Name: name}
+}
+
+func (*Queue) dialNoun() {}
+
+func joinEvents(events []CallStatusEvent) string {
+	s := ""
+	for i, event := range events {
+		if i > 0 {
+			s += " "
+		}
+
+		s += string(event)
+	}
+
+	return s
+}
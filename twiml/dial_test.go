@@ -0,0 +1,53 @@
+// +build unit
+
+package twiml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialNumberMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	d := r.Dial("+15108675310")
+	d.CallerID = "+14155552345"
+	d.Record = DialRecordFromAnswerDual
+	d.AnswerOnBridge = Bool(true)
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Dial callerId="+14155552345" record="record-from-answer-dual" answerOnBridge="true">+15108675310</Dial>`+
+		`</Response>`, string(b))
+}
+
+func TestDialNounsMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	d := r.Dial("")
+	d.Timeout = 20
+	d.Number("+15108675310").
+		SetStatusCallbackEvents(CallStatusEventInitiated, CallStatusEventAnswered).
+		StatusCallback = "https://example.com/status"
+	d.Client("alice")
+	d.Sip("sip:alice@example.com").Username = "admin"
+
+	c := r.Dial("").Conference("Room 1234")
+	c.Beep = ConferenceBeepOnEnter
+	c.StartConferenceOnEnter = Bool(false)
+	c.Record = ConferenceRecordFromStart
+
+	r.Dial("").Queue("support").URL = "https://example.com/about-to-connect"
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Dial timeout="20">`+
+		`<Number statusCallbackEvent="initiated answered" statusCallback="https://example.com/status">+15108675310</Number>`+
+		`<Client>alice</Client>`+
+		`<Sip username="admin">sip:alice@example.com</Sip>`+
+		`</Dial>`+
+		`<Dial><Conference beep="onEnter" startConferenceOnEnter="false" record="record-from-start">Room 1234</Conference></Dial>`+
+		`<Dial><Queue url="https://example.com/about-to-connect">support</Queue></Dial>`+
+		`</Response>`, string(b))
+}
@@ -0,0 +1,73 @@
+package twiml
+
+import "encoding/xml"
+
+// GatherVerb is a verb that can be nested in a Gather.
+type GatherVerb interface {
+	gatherVerb()
+}
+
+// GatherInput is the kind of input a Gather accepts.
+type GatherInput string
+
+// The inputs a Gather accepts.
+const (
+	GatherInputDTMF       GatherInput = "dtmf"
+	GatherInputSpeech     GatherInput = "speech"
+	GatherInputDTMFSpeech GatherInput = "dtmf speech"
+)
+
+// Gather collects digits or speech from the caller while playing its nested
+// verbs.
+type Gather struct {
+	XMLName                     xml.Name    `xml:"Gather"`
+	Input                       GatherInput `xml:"input,attr,omitempty"`
+	Action                      string      `xml:"action,attr,omitempty"`
+	Method                      Method      `xml:"method,attr,omitempty"`
+	Timeout                     *int        `xml:"timeout,attr"`
+	SpeechTimeout               string      `xml:"speechTimeout,attr,omitempty"`
+	FinishOnKey                 *string     `xml:"finishOnKey,attr"`
+	NumDigits                   int         `xml:"numDigits,attr,omitempty"`
+	Language                    string      `xml:"language,attr,omitempty"`
+	Hints                       string      `xml:"hints,attr,omitempty"`
+	SpeechModel                 string      `xml:"speechModel,attr,omitempty"`
+	ProfanityFilter             *bool       `xml:"profanityFilter,attr"`
+	PartialResultCallback       string      `xml:"partialResultCallback,attr,omitempty"`
+	PartialResultCallbackMethod Method      `xml:"partialResultCallbackMethod,attr,omitempty"`
+	ActionOnEmptyResult         *bool       `xml:"actionOnEmptyResult,attr"`
+	Verbs                       []GatherVerb
+}
+
+// NewGather will create a Gather with the default attributes.
+func NewGather() *Gather {
+	return &Gather{}
+}
+
+func (*Gather) voiceVerb() {}
+
+// Append adds verbs to play while gathering input.
+func (g *Gather) Append(verbs ...GatherVerb) *Gather {
+	g.Verbs = append(g.Verbs, verbs...)
+	return g
+}
+
+// Say adds text to be read while gathering input.
+func (g *Gather) Say(text string) *Say {
+	say := NewSay(text)
+	g.Append(say)
+	return say
+}
+
+// Play adds an audio file to be played while gathering input.
+func (g *Gather) Play(url string) *Play {
+	play := NewPlay(url)
+	g.Append(play)
+	return play
+}
+
+// Pause adds a pause while gathering input.
+func (g *Gather) Pause(length int) *Pause {
+	pause := NewPause(length)
+	g.Append(pause)
+	return pause
+}
@@ -0,0 +1,110 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// MessagingVerb is a verb that can be used in a MessagingResponse.
+type MessagingVerb interface {
+	messagingVerb()
+}
+
+// MessagingResponse is the root of a TwiML document that responds to an
+// incoming message.
+type MessagingResponse struct {
+	XMLName xml.Name `xml:"Response"`
+	Verbs   []MessagingVerb
+}
+
+// NewMessagingResponse will create an empty response.
+func NewMessagingResponse() *MessagingResponse {
+	return &MessagingResponse{}
+}
+
+// Append adds verbs to the response.
+func (r *MessagingResponse) Append(verbs ...MessagingVerb) *MessagingResponse {
+	r.Verbs = append(r.Verbs, verbs...)
+	return r
+}
+
+// Message adds a reply with an optional list of media URLs.
+func (r *MessagingResponse) Message(body string, mediaURLs ...string) *Message {
+	m := NewMessage(body, mediaURLs...)
+	r.Append(m)
+	return m
+}
+
+// Redirect adds a redirect to another TwiML document.
+func (r *MessagingResponse) Redirect(url string) *Redirect {
+	redirect := NewRedirect(url)
+	r.Append(redirect)
+	return redirect
+}
+
+// Marshal returns the response as an XML document.
+func (r *MessagingResponse) Marshal() ([]byte, error) {
+	return marshal(r)
+}
+
+// Write writes the response as an XML document.
+func (r *MessagingResponse) Write(w io.Writer) error {
+	return write(w, r)
+}
+
+// Message sends a message in reply. The text of the message is either
+// contained directly in the Message, with Text, or in a nested Body, which
+// allows media to be sent too.
+type Message struct {
+	XMLName        xml.Name `xml:"Message"`
+	To             string   `xml:"to,attr,omitempty"`
+	From           string   `xml:"from,attr,omitempty"`
+	Action         string   `xml:"action,attr,omitempty"`
+	Method         Method   `xml:"method,attr,omitempty"`
+	StatusCallback string   `xml:"statusCallback,attr,omitempty"`
+	Text           string   `xml:",chardata"`
+	Body           *Body
+	Media          []*Media
+}
+
+// NewMessage will create a message with an optional list of media URLs.
+func NewMessage(body string, mediaURLs ...string) *Message {
+	m := &Message{}
+	if body != "" {
+		m.Body = &Body{Text: body}
+	}
+
+	for _, url := range mediaURLs {
+		m.Media = append(m.Media, &Media{URL: url})
+	}
+
+	return m
+}
+
+func (*Message) messagingVerb() {}
+
+// Body is the text of a message.
+type Body struct {
+	XMLName xml.Name `xml:"Body"`
+	Text    string   `xml:",chardata"`
+}
+
+// Media is the URL of media to attach to a message.
+type Media struct {
+	XMLName xml.Name `xml:"Media"`
+	URL     string   `xml:",chardata"`
+}
+
+// Redirect transfers control to the TwiML document at another URL.
+type Redirect struct {
+	XMLName xml.Name `xml:"Redirect"`
+	Method  Method   `xml:"method,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// NewRedirect will create a redirect to the URL.
+func NewRedirect(url string) *Redirect {
+	return &Redirect{URL: url}
+}
+
+func (*Redirect) messagingVerb() {}
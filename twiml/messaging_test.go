@@ -0,0 +1,39 @@
+//go:build unit
+// +build unit
+
+package twiml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessagingResponseMarshal(t *testing.T) {
+	r := NewMessagingResponse()
+	r.Message("Hello & welcome!")
+	m := r.Message("", "https://example.com/cat.jpg")
+	m.To = "+15108675310"
+	m.StatusCallback = "https://example.com/status"
+	r.Redirect("https://example.com/next").Method = MethodGET
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>`+
+		`<Message><Body>Hello &amp; welcome!</Body></Message>`+
+		`<Message to="+15108675310" statusCallback="https://example.com/status">`+
+		`<Media>https://example.com/cat.jpg</Media></Message>`+
+		`<Redirect method="GET">https://example.com/next</Redirect>`+
+		`</Response>`, string(b))
+}
+
+func TestEmptyMessagingResponseWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewMessagingResponse().Write(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Response></Response>`, buf.String())
+}
@@ -0,0 +1,329 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// node is an element of a TwiML document before it is converted to a typed
+// verb or noun.
+type node struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*node
+	line     int
+	path     string
+}
+
+// parseNodes reads a TwiML document into a tree of nodes.
+func parseNodes(r io.Reader) (*node, error) {
+	dec := xml.NewDecoder(r)
+
+	var root *node
+	var stack []*node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			line, _ := dec.InputPos()
+			n := &node{
+				name:  tok.Name.Local,
+				attrs: tok.Copy().Attr,
+				line:  line,
+			}
+
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("line %d: more than one root element", line)
+				}
+
+				root = n
+				n.path = n.name
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				n.path = fmt.Sprintf("%s/%s[%d]", parent.path, n.name, len(parent.children))
+			}
+
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			n.text = strings.TrimSpace(n.text)
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(tok)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("empty document")
+	}
+
+	return root, nil
+}
+
+// decode unmarshals the node's attributes and text, and optionally its
+// children, into v.
+func (n *node) decode(v interface{}, includeChildren bool) error {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	err := n.encode(enc, includeChildren)
+	if err != nil {
+		return err
+	}
+
+	err = enc.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = xml.Unmarshal(buf.Bytes(), v)
+	if err != nil {
+		return err
+	}
+
+	clearXMLNames(reflect.ValueOf(v))
+	return nil
+}
+
+// clearXMLNames resets the XMLName fields that xml.Unmarshal fills in, so
+// that parsed values are equal to the ones the builder creates.
+func clearXMLNames(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearXMLNames(v.Elem())
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearXMLNames(v.Index(i))
+		}
+
+	case reflect.Struct:
+		if f := v.FieldByName("XMLName"); f.IsValid() && f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				clearXMLNames(v.Field(i))
+			}
+		}
+	}
+}
+
+func (n *node) encode(enc *xml.Encoder, includeChildren bool) error {
+	start := xml.StartElement{Name: xml.Name{Local: n.name}, Attr: n.attrs}
+	err := enc.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	if n.text != "" {
+		err = enc.EncodeToken(xml.CharData(n.text))
+		if err != nil {
+			return err
+		}
+	}
+
+	if includeChildren {
+		for _, child := range n.children {
+			err = child.encode(enc, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// ParseVoiceResponse reads a TwiML document into the same typed tree that
+// NewVoiceResponse builds. A *ValidationError is returned when the document
+// is not valid. Only the text of SSML elements in a Say is kept.
+func ParseVoiceResponse(r io.Reader) (*VoiceResponse, error) {
+	root, err := parseNodes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNodes(root, voiceSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := NewVoiceResponse()
+	for _, child := range root.children {
+		verb, err := decodeVoiceVerb(child)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Append(verb)
+	}
+
+	return resp, nil
+}
+
+// ParseMessagingResponse reads a TwiML document into the same typed tree
+// that NewMessagingResponse builds. A *ValidationError is returned when the
+// document is not valid.
+func ParseMessagingResponse(r io.Reader) (*MessagingResponse, error) {
+	root, err := parseNodes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNodes(root, messagingSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := NewMessagingResponse()
+	for _, child := range root.children {
+		var verb MessagingVerb
+		switch child.name {
+		case "Message":
+			verb = &Message{}
+		case "Redirect":
+			verb = &Redirect{}
+		}
+
+		err = child.decode(verb, true)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Append(verb)
+	}
+
+	return resp, nil
+}
+
+func decodeVoiceVerb(n *node) (VoiceVerb, error) {
+	switch n.name {
+	case "Gather":
+		gather := &Gather{}
+		err := n.decode(gather, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range n.children {
+			verb, err := decodeVoiceVerb(child)
+			if err != nil {
+				return nil, err
+			}
+
+			gather.Append(verb.(GatherVerb))
+		}
+
+		return gather, nil
+
+	case "Dial":
+		dial := &Dial{}
+		err := n.decode(dial, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range n.children {
+			noun := newDialNoun(child.name)
+			err = child.decode(noun, false)
+			if err != nil {
+				return nil, err
+			}
+
+			dial.Append(noun)
+		}
+
+		return dial, nil
+
+	case "Connect":
+		connect := &Connect{}
+		err := n.decode(connect, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range n.children {
+			stream := &Stream{}
+			err = child.decode(stream, true)
+			if err != nil {
+				return nil, err
+			}
+
+			connect.Append(stream)
+		}
+
+		return connect, nil
+
+	default:
+		verb := newVoiceVerb(n.name)
+		err := n.decode(verb, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return verb, nil
+	}
+}
+
+func newVoiceVerb(name string) VoiceVerb {
+	switch name {
+	case "Say":
+		return &Say{}
+	case "Play":
+		return &Play{}
+	case "Pause":
+		return &Pause{}
+	case "Record":
+		return &Record{}
+	case "Redirect":
+		return &Redirect{}
+	case "Hangup":
+		return &Hangup{}
+	case "Reject":
+		return &Reject{}
+	case "Enqueue":
+		return &Enqueue{}
+	case "Pay":
+		return &Pay{}
+	default:
+		return nil
+	}
+}
+
+func newDialNoun(name string) DialNoun {
+	switch name {
+	case "Number":
+		return &Number{}
+	case "Client":
+		return &Client{}
+	case "Sip":
+		return &Sip{}
+	case "Conference":
+		return &Conference{}
+	case "Queue":
+		return &Queue{}
+	default:
+		return nil
+	}
+}
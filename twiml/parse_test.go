@@ -0,0 +1,77 @@
+// +build unit
+
+package twiml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVoiceResponseRoundTrip(t *testing.T) {
+	r := NewVoiceResponse()
+	r.Say("Hello").Loop = Int(0)
+	g := r.Gather()
+	g.Input = GatherInputDTMF
+	g.NumDigits = 1
+	g.Say("Press 1 for sales.")
+	g.Pause(1)
+	d := r.Dial("")
+	d.Timeout = 20
+	d.Number("+15108675310").StatusCallback = "https://example.com/status"
+	d.Client("alice")
+	r.Dial("+15108675310").AnswerOnBridge = Bool(true)
+	r.Connect().Stream("wss://example.com/stream").Parameter("tenant", "acme")
+	r.Record().PlayBeep = Bool(false)
+	r.Hangup()
+
+	expected, err := r.Marshal()
+	assert.NoError(t, err)
+
+	parsed, err := ParseVoiceResponse(strings.NewReader(string(expected)))
+	assert.NoError(t, err)
+	assert.Equal(t, r, parsed)
+
+	actual, err := parsed.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestParseMessagingResponseRoundTrip(t *testing.T) {
+	r := NewMessagingResponse()
+	r.Message("Hello", "https://example.com/cat.jpg").StatusCallback = "https://example.com/status"
+	r.Redirect("https://example.com/next")
+
+	expected, err := r.Marshal()
+	assert.NoError(t, err)
+
+	parsed, err := ParseMessagingResponse(strings.NewReader(string(expected)))
+	assert.NoError(t, err)
+	assert.Equal(t, r, parsed)
+}
+
+func TestParseMessagingResponseTextRoundTrip(t *testing.T) {
+	r := NewMessagingResponse()
+	r.Append(&Message{To: "+15108675310", Text: "Hello"})
+
+	expected, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Contains(t, string(expected), `<Message to="+15108675310">Hello</Message>`)
+
+	parsed, err := ParseMessagingResponse(strings.NewReader(string(expected)))
+	assert.NoError(t, err)
+	assert.Equal(t, r, parsed)
+
+	actual, err := parsed.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestParseVoiceResponseInvalid(t *testing.T) {
+	_, err := ParseVoiceResponse(strings.NewReader(`<Response><Dial><Gather/></Dial></Response>`))
+	assert.IsType(t, &ValidationError{}, err)
+
+	_, err = ParseVoiceResponse(strings.NewReader(`<Response><Say>`))
+	assert.Error(t, err)
+}
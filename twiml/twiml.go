@@ -0,0 +1,52 @@
+// Package twiml builds TwiML documents, the XML instructions that tell
+// Twilio how to respond to incoming messages and calls.
+package twiml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Method is an HTTP method Twilio uses to request a URL.
+type Method string
+
+const (
+	// MethodGET requests the URL with GET.
+	MethodGET Method = "GET"
+
+	// MethodPOST requests the URL with POST.
+	MethodPOST Method = "POST"
+)
+
+// ContentType is the content type of TwiML documents.
+const ContentType = "text/xml; charset=utf-8"
+
+func marshal(v interface{}) ([]byte, error) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func write(w io.Writer, v interface{}) error {
+	b, err := marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// Bool returns a pointer to b for optional boolean attributes.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int returns a pointer to n for optional numeric attributes where zero is
+// meaningful.
+func Int(n int) *int {
+	return &n
+}
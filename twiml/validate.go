@@ -0,0 +1,599 @@
+package twiml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem is a single problem found while validating a TwiML document.
+type Problem struct {
+	// Path locates the element, such as Response/Gather[1]/Say[2].
+	Path    string
+	Line    int
+	Message string
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Path, p.Message)
+}
+
+// ValidationError is returned when a TwiML document is not valid.
+type ValidationError struct {
+	Problems []*Problem
+}
+
+func (err *ValidationError) Error() string {
+	lines := make([]string, len(err.Problems))
+	for i, p := range err.Problems {
+		lines[i] = p.String()
+	}
+
+	return "Invalid TwiML: " + strings.Join(lines, "; ")
+}
+
+// ValidateVoiceResponse validates a voice TwiML document. It returns a
+// *ValidationError listing every problem found, or another error when the
+// document is not well-formed XML.
+func ValidateVoiceResponse(r io.Reader) error {
+	root, err := parseNodes(r)
+	if err != nil {
+		return err
+	}
+
+	return validateNodes(root, voiceSchema)
+}
+
+// ValidateMessagingResponse validates a messaging TwiML document. It returns
+// a *ValidationError listing every problem found, or another error when the
+// document is not well-formed XML.
+func ValidateMessagingResponse(r io.Reader) error {
+	root, err := parseNodes(r)
+	if err != nil {
+		return err
+	}
+
+	return validateNodes(root, messagingSchema)
+}
+
+// Validate checks a built response against the same rules used for parsed
+// documents, such as attribute values and length limits.
+func (r *VoiceResponse) Validate() error {
+	b, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return ValidateVoiceResponse(bytes.NewReader(b))
+}
+
+// Validate checks a built response against the same rules used for parsed
+// documents, such as attribute values and length limits.
+func (r *MessagingResponse) Validate() error {
+	b, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return ValidateMessagingResponse(bytes.NewReader(b))
+}
+
+type attrKind int
+
+const (
+	attrString attrKind = iota
+	attrInt
+	attrBool
+	attrEnum
+	attrEnumList
+)
+
+type attrSpec struct {
+	kind   attrKind
+	values []string
+	min    int
+	max    int
+}
+
+type elementSpec struct {
+	attrs    map[string]attrSpec
+	children []string
+
+	// text is true when the element may contain text, and textRequired when
+	// it must.
+	text         bool
+	textRequired bool
+	maxText      int
+
+	// maxChildren limits the number of children with the given name.
+	maxChildren map[string]int
+}
+
+type schema struct {
+	root     string
+	elements map[string]*elementSpec
+}
+
+var (
+	methodAttr   = attrSpec{kind: attrEnum, values: []string{"GET", "POST"}}
+	stringAttr   = attrSpec{kind: attrString}
+	boolAttr     = attrSpec{kind: attrBool}
+	loopAttr     = attrSpec{kind: attrInt, min: 0, max: 1000}
+	trimAttr     = attrSpec{kind: attrEnum, values: []string{"trim-silence", "do-not-trim"}}
+	callbackAttr = attrSpec{kind: attrEnumList, values: []string{"initiated", "ringing", "answered", "completed"}}
+)
+
+func intAttr(min, max int) attrSpec {
+	return attrSpec{kind: attrInt, min: min, max: max}
+}
+
+func enumAttr(values ...string) attrSpec {
+	return attrSpec{kind: attrEnum, values: values}
+}
+
+func callbackAttrs(attrs map[string]attrSpec) map[string]attrSpec {
+	attrs["url"] = stringAttr
+	attrs["method"] = methodAttr
+	attrs["statusCallbackEvent"] = callbackAttr
+	attrs["statusCallback"] = stringAttr
+	attrs["statusCallbackMethod"] = methodAttr
+	return attrs
+}
+
+// ssmlChildren are the SSML elements that can be nested in a Say and in
+// each other. Paragraphs cannot be nested in paragraphs or sentences, and
+// sentences cannot be nested in sentences.
+var (
+	ssmlChildren = []string{
+		"break", "emphasis", "lang", "p", "phoneme", "prosody", "s", "say-as", "sub", "w",
+	}
+
+	ssmlInlineChildren = []string{
+		"break", "emphasis", "lang", "phoneme", "prosody", "say-as", "sub", "w",
+	}
+)
+
+var redirectSpec = &elementSpec{
+	attrs:        map[string]attrSpec{"method": methodAttr},
+	text:         true,
+	textRequired: true,
+}
+
+var voiceSchema = &schema{
+	root: "Response",
+	elements: map[string]*elementSpec{
+		"Response": {
+			children: []string{
+				"Say", "Play", "Pause", "Gather", "Dial", "Record", "Redirect",
+				"Hangup", "Reject", "Enqueue", "Pay", "Connect",
+			},
+		},
+		"Say": {
+			attrs: map[string]attrSpec{
+				"voice":    stringAttr,
+				"language": stringAttr,
+				"loop":     loopAttr,
+			},
+			children:     ssmlChildren,
+			text:         true,
+			textRequired: true,
+			maxText:      4096,
+		},
+		"break": {
+			attrs: map[string]attrSpec{
+				"strength": enumAttr("none", "x-weak", "weak", "medium", "strong", "x-strong"),
+				"time":     stringAttr,
+			},
+		},
+		"emphasis": {
+			attrs:    map[string]attrSpec{"level": enumAttr("strong", "moderate", "reduced")},
+			children: ssmlInlineChildren,
+			text:     true,
+		},
+		"lang": {
+			children: ssmlChildren,
+			text:     true,
+		},
+		"p": {
+			children: append([]string{"s"}, ssmlInlineChildren...),
+			text:     true,
+		},
+		"phoneme": {
+			attrs: map[string]attrSpec{
+				"alphabet": enumAttr("ipa", "x-sampa", "x-amazon-jyutping", "x-amazon-pinyin", "x-amazon-yomigana"),
+				"ph":       stringAttr,
+			},
+			text: true,
+		},
+		"prosody": {
+			attrs: map[string]attrSpec{
+				"volume": stringAttr,
+				"rate":   stringAttr,
+				"pitch":  stringAttr,
+			},
+			children: ssmlChildren,
+			text:     true,
+		},
+		"s": {
+			children: ssmlInlineChildren,
+			text:     true,
+		},
+		"say-as": {
+			attrs: map[string]attrSpec{
+				"interpret-as": stringAttr,
+				"format":       stringAttr,
+			},
+			text: true,
+		},
+		"sub": {
+			attrs: map[string]attrSpec{"alias": stringAttr},
+			text:  true,
+		},
+		"w": {
+			attrs: map[string]attrSpec{"role": stringAttr},
+			text:  true,
+		},
+		"Play": {
+			attrs: map[string]attrSpec{
+				"loop":   loopAttr,
+				"digits": stringAttr,
+			},
+			text: true,
+		},
+		"Pause": {
+			attrs: map[string]attrSpec{"length": intAttr(1, 600)},
+		},
+		"Gather": {
+			attrs: map[string]attrSpec{
+				"input":                       enumAttr("dtmf", "speech", "dtmf speech", "speech dtmf"),
+				"action":                      stringAttr,
+				"method":                      methodAttr,
+				"timeout":                     intAttr(0, 600),
+				"speechTimeout":               stringAttr,
+				"finishOnKey":                 enumAttr("", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "#", "*"),
+				"numDigits":                   intAttr(1, 256),
+				"language":                    stringAttr,
+				"hints":                       stringAttr,
+				"speechModel":                 stringAttr,
+				"profanityFilter":             boolAttr,
+				"partialResultCallback":       stringAttr,
+				"partialResultCallbackMethod": methodAttr,
+				"actionOnEmptyResult":         boolAttr,
+			},
+			children: []string{"Say", "Play", "Pause"},
+		},
+		"Dial": {
+			attrs: map[string]attrSpec{
+				"action":       stringAttr,
+				"method":       methodAttr,
+				"timeout":      intAttr(5, 600),
+				"hangupOnStar": boolAttr,
+				"timeLimit":    intAttr(1, 86400),
+				"callerId":     stringAttr,
+				"record": enumAttr(
+					"do-not-record",
+					"record-from-answer",
+					"record-from-ringing",
+					"record-from-answer-dual",
+					"record-from-ringing-dual"),
+				"trim":                          trimAttr,
+				"recordingStatusCallback":       stringAttr,
+				"recordingStatusCallbackMethod": methodAttr,
+				"answerOnBridge":                boolAttr,
+				"ringTone":                      stringAttr,
+			},
+			children:    []string{"Number", "Client", "Sip", "Conference", "Queue"},
+			text:        true,
+			maxChildren: map[string]int{"Number": 10, "Sip": 10},
+		},
+		"Number": {
+			attrs: callbackAttrs(map[string]attrSpec{
+				"sendDigits": stringAttr,
+				"byoc":       stringAttr,
+			}),
+			text:         true,
+			textRequired: true,
+		},
+		"Client": {
+			attrs:        callbackAttrs(map[string]attrSpec{}),
+			text:         true,
+			textRequired: true,
+		},
+		"Sip": {
+			attrs: callbackAttrs(map[string]attrSpec{
+				"username": stringAttr,
+				"password": stringAttr,
+			}),
+			text:         true,
+			textRequired: true,
+		},
+		"Conference": {
+			attrs: map[string]attrSpec{
+				"muted":                         boolAttr,
+				"beep":                          enumAttr("true", "false", "onEnter", "onExit"),
+				"startConferenceOnEnter":        boolAttr,
+				"endConferenceOnExit":           boolAttr,
+				"waitUrl":                       stringAttr,
+				"waitMethod":                    methodAttr,
+				"maxParticipants":               intAttr(2, 250),
+				"record":                        enumAttr("do-not-record", "record-from-start"),
+				"region":                        stringAttr,
+				"coach":                         stringAttr,
+				"trim":                          trimAttr,
+				"statusCallbackEvent":           attrSpec{kind: attrEnumList, values: []string{"start", "end", "join", "leave", "mute", "hold", "modify", "speaker", "announcement"}},
+				"statusCallback":                stringAttr,
+				"statusCallbackMethod":          methodAttr,
+				"recordingStatusCallback":       stringAttr,
+				"recordingStatusCallbackMethod": methodAttr,
+			},
+			text:         true,
+			textRequired: true,
+		},
+		"Queue": {
+			attrs: map[string]attrSpec{
+				"url":                 stringAttr,
+				"method":              methodAttr,
+				"reservationSid":      stringAttr,
+				"postWorkActivitySid": stringAttr,
+			},
+			text:         true,
+			textRequired: true,
+			maxText:      64,
+		},
+		"Record": {
+			attrs: map[string]attrSpec{
+				"action":                        stringAttr,
+				"method":                        methodAttr,
+				"timeout":                       intAttr(0, 600),
+				"finishOnKey":                   stringAttr,
+				"maxLength":                     intAttr(1, 14400),
+				"playBeep":                      boolAttr,
+				"trim":                          trimAttr,
+				"recordingStatusCallback":       stringAttr,
+				"recordingStatusCallbackMethod": methodAttr,
+				"recordingStatusCallbackEvent":  attrSpec{kind: attrEnumList, values: []string{"in-progress", "completed", "absent"}},
+				"transcribe":                    boolAttr,
+				"transcribeCallback":            stringAttr,
+			},
+		},
+		"Redirect": redirectSpec,
+		"Hangup":   {},
+		"Reject": {
+			attrs: map[string]attrSpec{"reason": enumAttr("rejected", "busy")},
+		},
+		"Enqueue": {
+			attrs: map[string]attrSpec{
+				"action":        stringAttr,
+				"method":        methodAttr,
+				"waitUrl":       stringAttr,
+				"waitUrlMethod": methodAttr,
+				"workflowSid":   stringAttr,
+			},
+			text:    true,
+			maxText: 64,
+		},
+		"Pay": {
+			attrs: map[string]attrSpec{
+				"input":                enumAttr("dtmf"),
+				"action":               stringAttr,
+				"statusCallback":       stringAttr,
+				"statusCallbackMethod": methodAttr,
+				"timeout":              intAttr(1, 600),
+				"maxAttempts":          intAttr(1, 3),
+				"securityCode":         boolAttr,
+				"postalCode":           stringAttr,
+				"paymentConnector":     stringAttr,
+				"paymentMethod":        enumAttr("credit-card", "ach-debit"),
+				"tokenType":            enumAttr("one-time", "reusable"),
+				"chargeAmount":         stringAttr,
+				"currency":             stringAttr,
+				"description":          stringAttr,
+				"validCardTypes":       stringAttr,
+				"language":             stringAttr,
+			},
+		},
+		"Connect": {
+			attrs: map[string]attrSpec{
+				"action": stringAttr,
+				"method": methodAttr,
+			},
+			children:    []string{"Stream"},
+			maxChildren: map[string]int{"Stream": 1},
+		},
+		"Stream": {
+			attrs: map[string]attrSpec{
+				"url":                  stringAttr,
+				"name":                 stringAttr,
+				"track":                enumAttr("inbound_track", "outbound_track", "both_tracks"),
+				"statusCallback":       stringAttr,
+				"statusCallbackMethod": methodAttr,
+			},
+			children: []string{"Parameter"},
+		},
+		"Parameter": {
+			attrs: map[string]attrSpec{
+				"name":  stringAttr,
+				"value": stringAttr,
+			},
+		},
+	},
+}
+
+var messagingSchema = &schema{
+	root: "Response",
+	elements: map[string]*elementSpec{
+		"Response": {
+			children: []string{"Message", "Redirect"},
+		},
+		"Message": {
+			attrs: map[string]attrSpec{
+				"to":             stringAttr,
+				"from":           stringAttr,
+				"action":         stringAttr,
+				"method":         methodAttr,
+				"statusCallback": stringAttr,
+			},
+			children:    []string{"Body", "Media"},
+			maxChildren: map[string]int{"Body": 1, "Media": 10},
+			text:        true,
+			maxText:     1600,
+		},
+		"Body": {
+			text:    true,
+			maxText: 1600,
+		},
+		"Media": {
+			text:         true,
+			textRequired: true,
+		},
+		"Redirect": redirectSpec,
+	},
+}
+
+// validateNodes validates a tree of nodes against a schema.
+func validateNodes(root *node, s *schema) error {
+	var problems []*Problem
+	report := func(n *node, format string, args ...interface{}) {
+		problems = append(problems, &Problem{
+			Path:    n.path,
+			Line:    n.line,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if root.name != s.root {
+		report(root, "root element must be <%s>", s.root)
+		return &ValidationError{Problems: problems}
+	}
+
+	var visit func(n *node, spec *elementSpec)
+	visit = func(n *node, spec *elementSpec) {
+		for _, attr := range n.attrs {
+			if attr.Name.Space != "" {
+				continue
+			}
+
+			a, ok := spec.attrs[attr.Name.Local]
+			if !ok {
+				report(n, "unknown attribute %q", attr.Name.Local)
+				continue
+			}
+
+			if msg := a.check(attr.Value); msg != "" {
+				report(n, "attribute %q %s", attr.Name.Local, msg)
+			}
+		}
+
+		switch {
+		case n.text != "" && !spec.text:
+			report(n, "<%s> cannot contain text", n.name)
+		case n.text == "" && spec.textRequired && len(n.children) == 0:
+			report(n, "<%s> must contain text", n.name)
+		case spec.maxText > 0 && utf8.RuneCountInString(n.text) > spec.maxText:
+			report(n, "text is longer than %d characters", spec.maxText)
+		}
+
+		counts := map[string]int{}
+		for _, child := range n.children {
+			childSpec, known := s.elements[child.name]
+			if !known {
+				report(child, "unknown element <%s>", child.name)
+				continue
+			}
+
+			if !contains(spec.children, child.name) {
+				report(child, "<%s> cannot be nested in <%s>", child.name, n.name)
+				continue
+			}
+
+			counts[child.name]++
+			if max, ok := spec.maxChildren[child.name]; ok && counts[child.name] == max+1 {
+				report(child, "<%s> cannot contain more than %d <%s>", n.name, max, child.name)
+			}
+
+			visit(child, childSpec)
+		}
+
+		if n.name == "Dial" && n.text != "" && len(n.children) > 0 {
+			report(n, "<Dial> cannot contain both a phone number and nouns")
+		}
+
+		if n.name == "Dial" && n.text == "" && len(n.children) == 0 {
+			report(n, "<Dial> must contain a phone number or nouns")
+		}
+
+		if n.name == "Stream" && n.attr("url") == "" {
+			report(n, "<Stream> requires a url attribute")
+		}
+
+		if n.name == "Message" && n.text != "" && len(n.children) > 0 {
+			report(n, "<Message> with nested <Body> or <Media> cannot contain text")
+		}
+	}
+
+	visit(root, s.elements[root.name])
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func (n *node) attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// check returns a description of the problem with the value, or an empty
+// string when it is valid.
+func (a attrSpec) check(value string) string {
+	switch a.kind {
+	case attrInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "must be an integer"
+		}
+
+		if n < a.min || n > a.max {
+			return fmt.Sprintf("must be between %d and %d", a.min, a.max)
+		}
+
+	case attrBool:
+		if value != "true" && value != "false" {
+			return "must be true or false"
+		}
+
+	case attrEnum:
+		if !contains(a.values, value) {
+			return fmt.Sprintf("must be one of %s", strings.Join(a.values, ", "))
+		}
+
+	case attrEnumList:
+		for _, v := range strings.Fields(value) {
+			if !contains(a.values, v) {
+				return fmt.Sprintf("must be a space separated list of %s", strings.Join(a.values, ", "))
+			}
+		}
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
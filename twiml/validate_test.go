@@ -0,0 +1,142 @@
+// +build unit
+
+package twiml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validationProblems(t *testing.T, err error) []string {
+	verr, ok := err.(*ValidationError)
+	if !assert.True(t, ok, "expected a *ValidationError, got %v", err) {
+		return nil
+	}
+
+	var problems []string
+	for _, p := range verr.Problems {
+		problems = append(problems, p.String())
+	}
+
+	return problems
+}
+
+func TestValidateVoiceResponseValid(t *testing.T) {
+	err := ValidateVoiceResponse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+  <Gather input="speech dtmf" numDigits="1" action="/menu">
+    <Say voice="alice">Press 1.</Say>
+  </Gather>
+  <Dial timeout="20" record="record-from-answer">
+    <Number statusCallbackEvent="initiated answered">+15108675310</Number>
+  </Dial>
+</Response>`))
+	assert.NoError(t, err)
+}
+
+func TestValidateVoiceResponseProblems(t *testing.T) {
+	err := ValidateVoiceResponse(strings.NewReader(`<Response>
+<Dance/>
+<Dial><Gather/></Dial>
+<Say loop="many">Hi</Say>
+<Record maxLength="20000" playBeep="yes"/>
+<Reject reason="nope"/>
+<Say>` + strings.Repeat("a", 4097) + `</Say>
+</Response>`))
+
+	assert.Equal(t, []string{
+		`line 2: Response/Dance[1]: unknown element <Dance>`,
+		`line 3: Response/Dial[2]/Gather[1]: <Gather> cannot be nested in <Dial>`,
+		`line 4: Response/Say[3]: attribute "loop" must be an integer`,
+		`line 5: Response/Record[4]: attribute "maxLength" must be between 1 and 14400`,
+		`line 5: Response/Record[4]: attribute "playBeep" must be true or false`,
+		`line 6: Response/Reject[5]: attribute "reason" must be one of rejected, busy`,
+		`line 7: Response/Say[6]: text is longer than 4096 characters`,
+	}, validationProblems(t, err))
+}
+
+func TestValidateVoiceResponseSSML(t *testing.T) {
+	err := ValidateVoiceResponse(strings.NewReader(`<Response>
+<Say voice="Polly.Joanna">Hi <break time="1s"/>
+<emphasis level="strong">there</emphasis>
+<lang xml:lang="fr-FR">Bonjour</lang>
+<p><s>Your code is <say-as interpret-as="digits">1234</say-as>.</s></p>
+<phoneme alphabet="ipa" ph="t&#x259;mei&#x325;&#x27E;ou&#x325;">tomato</phoneme>
+<prosody rate="slow" volume="loud">Slowly.</prosody>
+<sub alias="World Wide Web">WWW</sub>
+<w role="amazon:VBD">read</w>
+</Say>
+<Say><break time="500ms"/></Say>
+</Response>`))
+	assert.NoError(t, err)
+
+	err = ValidateVoiceResponse(strings.NewReader(`<Response>
+<Say><break strength="loud"/><p><p>Nested</p></p><dance/></Say>
+</Response>`))
+
+	assert.Equal(t, []string{
+		`line 2: Response/Say[1]/break[1]: attribute "strength" must be one of none, x-weak, weak, medium, strong, x-strong`,
+		`line 2: Response/Say[1]/p[2]/p[1]: <p> cannot be nested in <p>`,
+		`line 2: Response/Say[1]/dance[3]: unknown element <dance>`,
+	}, validationProblems(t, err))
+}
+
+func TestValidateVoiceResponseDial(t *testing.T) {
+	err := ValidateVoiceResponse(strings.NewReader(
+		`<Response><Dial>+15108675310<Client>alice</Client></Dial><Dial/></Response>`))
+
+	assert.Equal(t, []string{
+		`line 1: Response/Dial[1]: <Dial> cannot contain both a phone number and nouns`,
+		`line 1: Response/Dial[2]: <Dial> must contain a phone number or nouns`,
+	}, validationProblems(t, err))
+}
+
+func TestValidateMessagingResponseProblems(t *testing.T) {
+	err := ValidateMessagingResponse(strings.NewReader(`<Response>
+<Message method="PUT"><Body>` + strings.Repeat("a", 1601) + `</Body></Message>
+<Say>Hello</Say>
+</Response>`))
+
+	assert.Equal(t, []string{
+		`line 2: Response/Message[1]: attribute "method" must be one of GET, POST`,
+		`line 2: Response/Message[1]/Body[1]: text is longer than 1600 characters`,
+		`line 3: Response/Say[2]: unknown element <Say>`,
+	}, validationProblems(t, err))
+}
+
+func TestValidateMessagingResponseText(t *testing.T) {
+	err := ValidateMessagingResponse(strings.NewReader(
+		`<Response><Message>` + strings.Repeat("a", 1600) + `</Message></Response>`))
+	assert.NoError(t, err)
+
+	err = ValidateMessagingResponse(strings.NewReader(`<Response>
+<Message>` + strings.Repeat("a", 1601) + `</Message>
+<Message>Hello<Body>Hello</Body></Message>
+<Message>Hello<Media>https://example.com/cat.jpg</Media></Message>
+</Response>`))
+
+	assert.Equal(t, []string{
+		`line 2: Response/Message[1]: text is longer than 1600 characters`,
+		`line 3: Response/Message[2]: <Message> with nested <Body> or <Media> cannot contain text`,
+		`line 4: Response/Message[3]: <Message> with nested <Body> or <Media> cannot contain text`,
+	}, validationProblems(t, err))
+}
+
+func TestValidateWrongRoot(t *testing.T) {
+	err := ValidateMessagingResponse(strings.NewReader(`<Reply/>`))
+	assert.Equal(t, []string{
+		`line 1: Reply: root element must be <Response>`,
+	}, validationProblems(t, err))
+}
+
+func TestVoiceResponseValidate(t *testing.T) {
+	r := NewVoiceResponse()
+	r.Say("")
+	assert.Error(t, r.Validate())
+
+	r = NewVoiceResponse()
+	r.Say("Hello")
+	assert.NoError(t, r.Validate())
+}
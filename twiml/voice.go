@@ -0,0 +1,314 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// VoiceVerb is a verb that can be used in a VoiceResponse.
+type VoiceVerb interface {
+	voiceVerb()
+}
+
+// VoiceResponse is the root of a TwiML document that controls a call.
+type VoiceResponse struct {
+	XMLName xml.Name `xml:"Response"`
+	Verbs   []VoiceVerb
+}
+
+// NewVoiceResponse will create an empty response.
+func NewVoiceResponse() *VoiceResponse {
+	return &VoiceResponse{}
+}
+
+// Append adds verbs to the response.
+func (r *VoiceResponse) Append(verbs ...VoiceVerb) *VoiceResponse {
+	r.Verbs = append(r.Verbs, verbs...)
+	return r
+}
+
+// Say adds text to be read to the caller.
+func (r *VoiceResponse) Say(text string) *Say {
+	say := NewSay(text)
+	r.Append(say)
+	return say
+}
+
+// Play adds an audio file to be played to the caller.
+func (r *VoiceResponse) Play(url string) *Play {
+	play := NewPlay(url)
+	r.Append(play)
+	return play
+}
+
+// Pause adds a pause of the given number of seconds.
+func (r *VoiceResponse) Pause(length int) *Pause {
+	pause := NewPause(length)
+	r.Append(pause)
+	return pause
+}
+
+// Gather adds a prompt that collects digits or speech from the caller.
+func (r *VoiceResponse) Gather() *Gather {
+	gather := NewGather()
+	r.Append(gather)
+	return gather
+}
+
+// Dial adds a dial that connects the caller to another party. The number is
+// optional and nouns can be added to the returned dial instead.
+func (r *VoiceResponse) Dial(number string) *Dial {
+	dial := NewDial(number)
+	r.Append(dial)
+	return dial
+}
+
+// Record adds a recording of the caller's voice.
+func (r *VoiceResponse) Record() *Record {
+	record := NewRecord()
+	r.Append(record)
+	return record
+}
+
+// Redirect adds a redirect to another TwiML document.
+func (r *VoiceResponse) Redirect(url string) *Redirect {
+	redirect := NewRedirect(url)
+	r.Append(redirect)
+	return redirect
+}
+
+// Hangup adds a hangup that ends the call.
+func (r *VoiceResponse) Hangup() *Hangup {
+	hangup := NewHangup()
+	r.Append(hangup)
+	return hangup
+}
+
+// Reject adds a rejection of an incoming call without answering it.
+func (r *VoiceResponse) Reject(reason RejectReason) *Reject {
+	reject := NewReject(reason)
+	r.Append(reject)
+	return reject
+}
+
+// Enqueue adds the caller to the named queue.
+func (r *VoiceResponse) Enqueue(name string) *Enqueue {
+	enqueue := NewEnqueue(name)
+	r.Append(enqueue)
+	return enqueue
+}
+
+// Pay adds a payment capture.
+func (r *VoiceResponse) Pay() *Pay {
+	pay := NewPay()
+	r.Append(pay)
+	return pay
+}
+
+// Connect adds a connection to another service, such as a media stream.
+func (r *VoiceResponse) Connect() *Connect {
+	connect := NewConnect()
+	r.Append(connect)
+	return connect
+}
+
+// Marshal returns the response as an XML document.
+func (r *VoiceResponse) Marshal() ([]byte, error) {
+	return marshal(r)
+}
+
+// Write writes the response as an XML document.
+func (r *VoiceResponse) Write(w io.Writer) error {
+	return write(w, r)
+}
+
+func (*Redirect) voiceVerb() {}
+
+// Voice is the voice used to read text.
+type Voice string
+
+// The basic voices. Amazon Polly and Google voices, such as Polly.Joanna,
+// can be used by converting their names to a Voice.
+const (
+	VoiceMan   Voice = "man"
+	VoiceWoman Voice = "woman"
+	VoiceAlice Voice = "alice"
+)
+
+// Say reads text to the caller.
+type Say struct {
+	XMLName  xml.Name `xml:"Say"`
+	Voice    Voice    `xml:"voice,attr,omitempty"`
+	Language string   `xml:"language,attr,omitempty"`
+	Loop     *int     `xml:"loop,attr"`
+	Text     string   `xml:",chardata"`
+}
+
+// NewSay will create a Say that reads the text.
+func NewSay(text string) *Say {
+	return &Say{Text: text}
+}
+
+func (*Say) voiceVerb()  {}
+func (*Say) gatherVerb() {}
+
+// Play plays an audio file or DTMF tones to the caller.
+type Play struct {
+	XMLName xml.Name `xml:"Play"`
+	Loop    *int     `xml:"loop,attr"`
+	Digits  string   `xml:"digits,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// NewPlay will create a Play for the audio file at the URL.
+func NewPlay(url string) *Play {
+	return &Play{URL: url}
+}
+
+func (*Play) voiceVerb()  {}
+func (*Play) gatherVerb() {}
+
+// Pause waits silently.
+type Pause struct {
+	XMLName xml.Name `xml:"Pause"`
+	Length  int      `xml:"length,attr,omitempty"`
+}
+
+// NewPause will create a Pause of the given number of seconds.
+func NewPause(length int) *Pause {
+	return &Pause{Length: length}
+}
+
+func (*Pause) voiceVerb()  {}
+func (*Pause) gatherVerb() {}
+
+// RecordTrim determines whether silence is trimmed from a recording.
+type RecordTrim string
+
+// The trim options for recordings.
+const (
+	RecordTrimSilence RecordTrim = "trim-silence"
+	RecordDoNotTrim   RecordTrim = "do-not-trim"
+)
+
+// Record records the caller's voice.
+type Record struct {
+	XMLName                       xml.Name   `xml:"Record"`
+	Action                        string     `xml:"action,attr,omitempty"`
+	Method                        Method     `xml:"method,attr,omitempty"`
+	Timeout                       *int       `xml:"timeout,attr"`
+	FinishOnKey                   string     `xml:"finishOnKey,attr,omitempty"`
+	MaxLength                     int        `xml:"maxLength,attr,omitempty"`
+	PlayBeep                      *bool      `xml:"playBeep,attr"`
+	Trim                          RecordTrim `xml:"trim,attr,omitempty"`
+	RecordingStatusCallback       string     `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod Method     `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	RecordingStatusCallbackEvent  string     `xml:"recordingStatusCallbackEvent,attr,omitempty"`
+	Transcribe                    *bool      `xml:"transcribe,attr"`
+	TranscribeCallback            string     `xml:"transcribeCallback,attr,omitempty"`
+}
+
+// NewRecord will create a Record with the default attributes.
+func NewRecord() *Record {
+	return &Record{}
+}
+
+func (*Record) voiceVerb() {}
+
+// Hangup ends the call.
+type Hangup struct {
+	XMLName xml.Name `xml:"Hangup"`
+}
+
+// NewHangup will create a Hangup.
+func NewHangup() *Hangup {
+	return &Hangup{}
+}
+
+func (*Hangup) voiceVerb() {}
+
+// RejectReason is the reason given when rejecting a call.
+type RejectReason string
+
+// The reasons for rejecting a call.
+const (
+	RejectReasonRejected RejectReason = "rejected"
+	RejectReasonBusy     RejectReason = "busy"
+)
+
+// Reject rejects an incoming call without answering it.
+type Reject struct {
+	XMLName xml.Name     `xml:"Reject"`
+	Reason  RejectReason `xml:"reason,attr,omitempty"`
+}
+
+// NewReject will create a Reject with the reason, which may be empty.
+func NewReject(reason RejectReason) *Reject {
+	return &Reject{Reason: reason}
+}
+
+func (*Reject) voiceVerb() {}
+
+// Enqueue places the caller in a queue.
+type Enqueue struct {
+	XMLName       xml.Name `xml:"Enqueue"`
+	Action        string   `xml:"action,attr,omitempty"`
+	Method        Method   `xml:"method,attr,omitempty"`
+	WaitURL       string   `xml:"waitUrl,attr,omitempty"`
+	WaitURLMethod Method   `xml:"waitUrlMethod,attr,omitempty"`
+	WorkflowSID   string   `xml:"workflowSid,attr,omitempty"`
+	Name          string   `xml:",chardata"`
+}
+
+// NewEnqueue will create an Enqueue for the named queue.
+func NewEnqueue(name string) *Enqueue {
+	return &Enqueue{Name: name}
+}
+
+func (*Enqueue) voiceVerb() {}
+
+// PaymentMethod is the kind of payment captured by Pay.
+type PaymentMethod string
+
+// The payment methods.
+const (
+	PaymentMethodCreditCard PaymentMethod = "credit-card"
+	PaymentMethodACHDebit   PaymentMethod = "ach-debit"
+)
+
+// TokenType is the kind of token created by Pay.
+type TokenType string
+
+// The token types.
+const (
+	TokenTypeOneTime  TokenType = "one-time"
+	TokenTypeReusable TokenType = "reusable"
+)
+
+// Pay captures payment information from the caller.
+type Pay struct {
+	XMLName              xml.Name      `xml:"Pay"`
+	Input                string        `xml:"input,attr,omitempty"`
+	Action               string        `xml:"action,attr,omitempty"`
+	StatusCallback       string        `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod Method        `xml:"statusCallbackMethod,attr,omitempty"`
+	Timeout              int           `xml:"timeout,attr,omitempty"`
+	MaxAttempts          int           `xml:"maxAttempts,attr,omitempty"`
+	SecurityCode         *bool         `xml:"securityCode,attr"`
+	PostalCode           string        `xml:"postalCode,attr,omitempty"`
+	PaymentConnector     string        `xml:"paymentConnector,attr,omitempty"`
+	PaymentMethod        PaymentMethod `xml:"paymentMethod,attr,omitempty"`
+	TokenType            TokenType     `xml:"tokenType,attr,omitempty"`
+	ChargeAmount         string        `xml:"chargeAmount,attr,omitempty"`
+	Currency             string        `xml:"currency,attr,omitempty"`
+	Description          string        `xml:"description,attr,omitempty"`
+	ValidCardTypes       string        `xml:"validCardTypes,attr,omitempty"`
+	Language             string        `xml:"language,attr,omitempty"`
+}
+
+// NewPay will create a Pay with the default attributes.
+func NewPay() *Pay {
+	return &Pay{}
+}
+
+func (*Pay) voiceVerb() {}
@@ -0,0 +1,79 @@
+// +build unit
+
+package twiml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const header = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+func TestVoiceResponseMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	say := r.Say("Hello")
+	say.Voice = VoiceAlice
+	say.Loop = Int(0)
+	r.Play("https://example.com/hello.mp3").Digits = "wwww3"
+	r.Pause(2)
+	record := r.Record()
+	record.MaxLength = 30
+	record.PlayBeep = Bool(false)
+	record.Trim = RecordDoNotTrim
+	r.Enqueue("support").WaitURL = "https://example.com/wait"
+	r.Redirect("https://example.com/next")
+	r.Reject(RejectReasonBusy)
+	r.Hangup()
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Say voice="alice" loop="0">Hello</Say>`+
+		`<Play digits="wwww3">https://example.com/hello.mp3</Play>`+
+		`<Pause length="2"></Pause>`+
+		`<Record maxLength="30" playBeep="false" trim="do-not-trim"></Record>`+
+		`<Enqueue waitUrl="https://example.com/wait">support</Enqueue>`+
+		`<Redirect>https://example.com/next</Redirect>`+
+		`<Reject reason="busy"></Reject>`+
+		`<Hangup></Hangup>`+
+		`</Response>`, string(b))
+}
+
+func TestGatherMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	g := r.Gather()
+	g.Input = GatherInputDTMFSpeech
+	g.Action = "https://example.com/menu"
+	g.Method = MethodPOST
+	g.NumDigits = 1
+	g.Timeout = Int(5)
+	g.Say("Press 1 for sales.")
+	g.Pause(1)
+	g.Play("https://example.com/jingle.mp3")
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Gather input="dtmf speech" action="https://example.com/menu" method="POST" timeout="5" numDigits="1">`+
+		`<Say>Press 1 for sales.</Say>`+
+		`<Pause length="1"></Pause>`+
+		`<Play>https://example.com/jingle.mp3</Play>`+
+		`</Gather>`+
+		`</Response>`, string(b))
+}
+
+func TestPayMarshal(t *testing.T) {
+	r := NewVoiceResponse()
+	pay := r.Pay()
+	pay.ChargeAmount = "10.00"
+	pay.PaymentMethod = PaymentMethodCreditCard
+	pay.TokenType = TokenTypeReusable
+	pay.SecurityCode = Bool(true)
+
+	b, err := r.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, header+`<Response>`+
+		`<Pay securityCode="true" paymentMethod="credit-card" tokenType="reusable" chargeAmount="10.00"></Pay>`+
+		`</Response>`, string(b))
+}
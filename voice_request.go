@@ -0,0 +1,233 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jeremybower/go-twilio/twiml"
+)
+
+// CallStatus is the status of a call.
+type CallStatus string
+
+// The statuses a call can have.
+const (
+	CallStatusQueued     CallStatus = "queued"
+	CallStatusInitiated  CallStatus = "initiated"
+	CallStatusRinging    CallStatus = "ringing"
+	CallStatusInProgress CallStatus = "in-progress"
+	CallStatusCompleted  CallStatus = "completed"
+	CallStatusBusy       CallStatus = "busy"
+	CallStatusFailed     CallStatus = "failed"
+	CallStatusNoAnswer   CallStatus = "no-answer"
+	CallStatusCanceled   CallStatus = "canceled"
+)
+
+// Final returns true when the call has ended.
+func (status CallStatus) Final() bool {
+	switch status {
+	case CallStatusCompleted,
+		CallStatusBusy,
+		CallStatusFailed,
+		CallStatusNoAnswer,
+		CallStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CallDirection is the direction of a call.
+type CallDirection string
+
+// The directions of a call.
+const (
+	CallDirectionInbound      CallDirection = "inbound"
+	CallDirectionOutboundAPI  CallDirection = "outbound-api"
+	CallDirectionOutboundDial CallDirection = "outbound-dial"
+)
+
+// VoiceRequest is a request for TwiML during a call, as sent by Twilio to a
+// voice webhook or to the action URL of a verb such as Gather or Record.
+type VoiceRequest struct {
+	CallSID       string
+	AccountSID    string
+	ParentCallSID string
+	From          string
+	To            string
+	CallStatus    CallStatus
+	Direction     CallDirection
+	ForwardedFrom string
+	CallerName    string
+	APIVersion    string
+
+	FromCity    string
+	FromState   string
+	FromZip     string
+	FromCountry string
+	ToCity      string
+	ToState     string
+	ToZip       string
+	ToCountry   string
+
+	// Digits are the digits gathered by a Gather or pressed to end a
+	// Record.
+	Digits string
+
+	// SpeechResult is the transcribed speech gathered by a Gather, and
+	// Confidence is between 0 and 1.
+	SpeechResult string
+	Confidence   float64
+
+	// RecordingURL, RecordingSID and RecordingDuration describe the
+	// recording made by a Record.
+	RecordingURL      string
+	RecordingSID      string
+	RecordingDuration int
+
+	// DialCallStatus, DialCallSID and DialCallDuration describe the call
+	// made by a Dial.
+	DialCallStatus   CallStatus
+	DialCallSID      string
+	DialCallDuration int
+
+	// Form contains every parameter sent by Twilio, including ones without a
+	// field above.
+	Form url.Values
+}
+
+// ParseVoiceRequest parses the parameters of a voice webhook.
+func ParseVoiceRequest(form url.Values) (*VoiceRequest, error) {
+	req := &VoiceRequest{
+		CallSID:        form.Get("CallSid"),
+		AccountSID:     form.Get("AccountSid"),
+		ParentCallSID:  form.Get("ParentCallSid"),
+		From:           form.Get("From"),
+		To:             form.Get("To"),
+		CallStatus:     CallStatus(form.Get("CallStatus")),
+		Direction:      CallDirection(form.Get("Direction")),
+		ForwardedFrom:  form.Get("ForwardedFrom"),
+		CallerName:     form.Get("CallerName"),
+		APIVersion:     form.Get("ApiVersion"),
+		FromCity:       form.Get("FromCity"),
+		FromState:      form.Get("FromState"),
+		FromZip:        form.Get("FromZip"),
+		FromCountry:    form.Get("FromCountry"),
+		ToCity:         form.Get("ToCity"),
+		ToState:        form.Get("ToState"),
+		ToZip:          form.Get("ToZip"),
+		ToCountry:      form.Get("ToCountry"),
+		Digits:         form.Get("Digits"),
+		SpeechResult:   form.Get("SpeechResult"),
+		RecordingURL:   form.Get("RecordingUrl"),
+		RecordingSID:   form.Get("RecordingSid"),
+		DialCallStatus: CallStatus(form.Get("DialCallStatus")),
+		DialCallSID:    form.Get("DialCallSid"),
+		Form:           form,
+	}
+
+	if req.CallSID == "" {
+		return nil, fmt.Errorf("Missing parameter: CallSid")
+	}
+
+	var err error
+	req.Confidence, err = parseFloatParam(form, "Confidence")
+	if err != nil {
+		return nil, err
+	}
+
+	req.RecordingDuration, err = parseIntParam(form, "RecordingDuration")
+	if err != nil {
+		return nil, err
+	}
+
+	req.DialCallDuration, err = parseIntParam(form, "DialCallDuration")
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// VoiceRequestHandlerFunc handles a voice request and returns the TwiML for
+// the call. A nil response sends an empty response, which ends the call.
+type VoiceRequestHandlerFunc func(
+	ctx context.Context,
+	req *VoiceRequest,
+) (*twiml.VoiceResponse, error)
+
+// VoiceRequestHandler is an http.Handler for voice webhooks. It validates the
+// request's signature, parses the voice request and writes the TwiML
+// returned by its handler function.
+type VoiceRequestHandler struct {
+	validator *RequestValidator
+	handler   VoiceRequestHandlerFunc
+}
+
+// NewVoiceRequestHandler will create a handler that validates requests with
+// the validator and handles them with the function. Requests are not
+// validated when the validator is nil.
+func NewVoiceRequestHandler(
+	validator *RequestValidator,
+	handler VoiceRequestHandlerFunc,
+) *VoiceRequestHandler {
+	return &VoiceRequestHandler{
+		validator: validator,
+		handler:   handler,
+	}
+}
+
+func (h *VoiceRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form, ok := validateWebhook(h.validator, w, r)
+	if !ok {
+		return
+	}
+
+	req, err := ParseVoiceRequest(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.handler(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeVoiceResponse(w, resp)
+}
+
+// writeVoiceResponse writes the TwiML, or an empty response when it is nil.
+func writeVoiceResponse(w http.ResponseWriter, resp *twiml.VoiceResponse) {
+	if resp == nil {
+		resp = twiml.NewVoiceResponse()
+	}
+
+	b, err := resp.Marshal()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", twiml.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func parseFloatParam(form url.Values, key string) (float64, error) {
+	value := form.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid parameter: %s", key)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,98 @@
+// +build unit
+
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jeremybower/go-twilio/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVoiceRequest(t *testing.T) {
+	req, err := ParseVoiceRequest(url.Values{
+		"CallSid":           {"CA1"},
+		"AccountSid":        {"AC1"},
+		"From":              {"+15108675310"},
+		"To":                {"+14155552345"},
+		"CallStatus":        {"in-progress"},
+		"Direction":         {"inbound"},
+		"Digits":            {"1"},
+		"SpeechResult":      {"sales please"},
+		"Confidence":        {"0.87"},
+		"RecordingUrl":      {"https://api.twilio.com/recording"},
+		"RecordingDuration": {"12"},
+		"DialCallStatus":    {"no-answer"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CA1", req.CallSID)
+	assert.Equal(t, CallStatusInProgress, req.CallStatus)
+	assert.Equal(t, CallDirectionInbound, req.Direction)
+	assert.Equal(t, "1", req.Digits)
+	assert.Equal(t, "sales please", req.SpeechResult)
+	assert.Equal(t, 0.87, req.Confidence)
+	assert.Equal(t, 12, req.RecordingDuration)
+	assert.Equal(t, CallStatusNoAnswer, req.DialCallStatus)
+	assert.True(t, req.DialCallStatus.Final())
+
+	_, err = ParseVoiceRequest(url.Values{"CallSid": {"CA1"}, "Confidence": {"high"}})
+	assert.EqualError(t, err, "Invalid parameter: Confidence")
+
+	_, err = ParseVoiceRequest(url.Values{})
+	assert.EqualError(t, err, "Missing parameter: CallSid")
+}
+
+func TestVoiceRequestHandler(t *testing.T) {
+	handler := NewVoiceRequestHandler(
+		NewRequestValidator("token"),
+		func(ctx context.Context, req *VoiceRequest) (*twiml.VoiceResponse, error) {
+			assert.Equal(t, "CA1", req.CallSID)
+
+			r := twiml.NewVoiceResponse()
+			r.Say("Hello")
+			return r, nil
+		})
+
+	form := url.Values{"CallSid": {"CA1"}, "CallStatus": {"ringing"}}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/voice", form))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, twiml.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<Response><Say>Hello</Say></Response>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("wrong", "http://example.com/voice", form))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestVoiceRequestHandlerErrors(t *testing.T) {
+	handler := NewVoiceRequestHandler(nil,
+		func(ctx context.Context, req *VoiceRequest) (*twiml.VoiceResponse, error) {
+			if req.Digits == "" {
+				return nil, nil
+			}
+
+			return nil, errors.New("test error")
+		})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/voice", url.Values{"CallSid": {"CA1"}}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<Response></Response>")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/voice", url.Values{"CallSid": {"CA1"}, "Digits": {"1"}}))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedRequest("", "http://example.com/voice", url.Values{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
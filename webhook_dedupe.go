@@ -0,0 +1,291 @@
+package twilio
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WebhookResponse is a response written by a webhook handler.
+type WebhookResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// WebhookDedupeStore remembers the responses to handled webhooks.
+type WebhookDedupeStore interface {
+	// Get returns the response for the key or nil when the webhook has not
+	// been handled.
+	Get(key string) (*WebhookResponse, error)
+
+	// Put remembers the response for the key until it expires.
+	Put(key string, resp *WebhookResponse, expiresAt time.Time) error
+}
+
+// MemoryWebhookDedupeStore keeps webhook responses in memory. It is useful
+// for tests and for processes that do not need to survive a restart.
+type MemoryWebhookDedupeStore struct {
+	mu        sync.Mutex
+	responses map[string]*memoryWebhookResponse
+	sweptAt   time.Time
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// memoryWebhookSweepInterval is how often Put removes expired responses.
+const memoryWebhookSweepInterval = time.Minute
+
+type memoryWebhookResponse struct {
+	resp      *WebhookResponse
+	expiresAt time.Time
+}
+
+// NewMemoryWebhookDedupeStore will create an empty in-memory store.
+func NewMemoryWebhookDedupeStore() *MemoryWebhookDedupeStore {
+	return &MemoryWebhookDedupeStore{
+		responses: map[string]*memoryWebhookResponse{},
+		Now:       time.Now,
+	}
+}
+
+// Get returns the response for the key or nil when the webhook has not been
+// handled or its response has expired.
+func (store *MemoryWebhookDedupeStore) Get(key string) (*WebhookResponse, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry, ok := store.responses[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !store.Now().Before(entry.expiresAt) {
+		delete(store.responses, key)
+		return nil, nil
+	}
+
+	return entry.resp, nil
+}
+
+// Put remembers the response for the key until it expires. Expired responses
+// are removed at most once a minute.
+func (store *MemoryWebhookDedupeStore) Put(key string, resp *WebhookResponse, expiresAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.Now()
+	if now.Sub(store.sweptAt) >= memoryWebhookSweepInterval {
+		for k, entry := range store.responses {
+			if !now.Before(entry.expiresAt) {
+				delete(store.responses, k)
+			}
+		}
+
+		store.sweptAt = now
+	}
+
+	store.responses[key] = &memoryWebhookResponse{resp: resp, expiresAt: expiresAt}
+	return nil
+}
+
+// The parameters that identify a webhook's resource and event, in order of
+// preference.
+var (
+	webhookSIDParams = []string{
+		"TranscriptionSid",
+		"RecordingSid",
+		"ConferenceSid",
+		"MessageSid",
+		"SmsSid",
+		"CallSid",
+	}
+
+	webhookEventParams = []string{
+		"TranscriptionStatus",
+		"RecordingStatus",
+		"StatusCallbackEvent",
+		"MessageStatus",
+		"SmsStatus",
+		"CallStatus",
+	}
+)
+
+// WebhookKey returns the key that identifies a webhook: the URL, the SID of
+// the resource and the type of event. It returns an empty string for
+// webhooks that cannot be identified, including the voice webhooks that
+// return TwiML during a call, which may be requested more than once.
+func WebhookKey(r *http.Request, form url.Values) string {
+	sid := firstParam(form, webhookSIDParams)
+	if sid == "" {
+		return ""
+	}
+
+	if sid == form.Get("CallSid") && form.Get("CallbackSource") == "" {
+		return ""
+	}
+
+	key := r.URL.RequestURI() + " " + sid + "/" + firstParam(form, webhookEventParams)
+
+	// Conference events are sent for each participant's call.
+	if callSID := form.Get("CallSid"); callSID != "" && callSID != sid {
+		key += "/" + callSID
+	}
+
+	if sequence := form.Get("SequenceNumber"); sequence != "" {
+		key += "/" + sequence
+	}
+
+	return key
+}
+
+func firstParam(form url.Values, keys []string) string {
+	for _, key := range keys {
+		if value := form.Get(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// WebhookDeduper ignores webhooks that Twilio retries after they have been
+// handled. The response to the first delivery is remembered and written
+// again for retries, so that TwiML replies are not lost.
+type WebhookDeduper struct {
+	store WebhookDedupeStore
+
+	// Key returns the key that identifies a webhook, or an empty string to
+	// handle the webhook without deduplication. It defaults to WebhookKey.
+	Key func(r *http.Request, form url.Values) string
+
+	// Window is how long a handled webhook is remembered.
+	Window time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	locks keyLocks
+}
+
+// NewWebhookDeduper will create a deduper that remembers handled webhooks in
+// the store.
+func NewWebhookDeduper(store WebhookDedupeStore) *WebhookDeduper {
+	return &WebhookDeduper{
+		store:  store,
+		Key:    WebhookKey,
+		Window: 24 * time.Hour,
+		Now:    time.Now,
+	}
+}
+
+// Middleware handles each webhook once. Use it after the request validator
+// so that unsigned requests are not remembered. Responses with a 5xx status
+// are not remembered so that Twilio's retry is handled again.
+func (d *WebhookDeduper) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		key := d.Key(r, r.Form)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.serve(w, key, d.Window, func(w http.ResponseWriter) {
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// serve writes the remembered response for the key, or calls handle and
+// remembers its response for the window. Concurrent deliveries of the same
+// webhook wait for the first.
+func (d *WebhookDeduper) serve(
+	w http.ResponseWriter,
+	key string,
+	window time.Duration,
+	handle func(w http.ResponseWriter),
+) {
+	defer d.locks.lock(key)()
+
+	resp, err := d.store.Get(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if resp != nil {
+		writeWebhookResponse(w, resp)
+		return
+	}
+
+	rec := newWebhookResponseRecorder(w)
+	handle(rec)
+
+	resp = rec.response()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return
+	}
+
+	// The response has been written, so an error cannot be reported. The
+	// webhook is handled again if it is retried.
+	d.store.Put(key, resp, d.Now().Add(window))
+}
+
+// webhookResponseRecorder writes a response and records a copy of it.
+type webhookResponseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newWebhookResponseRecorder(w http.ResponseWriter) *webhookResponseRecorder {
+	return &webhookResponseRecorder{ResponseWriter: w}
+}
+
+func (rec *webhookResponseRecorder) WriteHeader(statusCode int) {
+	if rec.statusCode == 0 {
+		rec.statusCode = statusCode
+	}
+
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *webhookResponseRecorder) Write(b []byte) (int, error) {
+	if rec.statusCode == 0 {
+		rec.statusCode = http.StatusOK
+	}
+
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *webhookResponseRecorder) response() *WebhookResponse {
+	statusCode := rec.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	return &WebhookResponse{
+		StatusCode:  statusCode,
+		ContentType: rec.Header().Get("Content-Type"),
+		Body:        rec.body.Bytes(),
+	}
+}
+
+func writeWebhookResponse(w http.ResponseWriter, resp *WebhookResponse) {
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	w.Write(resp.Body)
+}
@@ -0,0 +1,114 @@
+// +build unit
+
+package twilio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/status?tenant=1", nil)
+
+	assert.Equal(t, "/status?tenant=1 SM1/delivered", WebhookKey(r, url.Values{
+		"MessageSid":    {"SM1"},
+		"MessageStatus": {"delivered"},
+	}))
+	assert.Equal(t, "/status?tenant=1 CA1/completed", WebhookKey(r, url.Values{
+		"CallSid":        {"CA1"},
+		"CallStatus":     {"completed"},
+		"CallbackSource": {"call-progress-events"},
+	}))
+	assert.Equal(t, "/status?tenant=1 CF1/participant-join/CA1/3", WebhookKey(r, url.Values{
+		"ConferenceSid":       {"CF1"},
+		"CallSid":             {"CA1"},
+		"StatusCallbackEvent": {"participant-join"},
+		"SequenceNumber":      {"3"},
+	}))
+	assert.Equal(t, "", WebhookKey(r, url.Values{
+		"CallSid":    {"CA1"},
+		"CallStatus": {"in-progress"},
+	}))
+	assert.Equal(t, "", WebhookKey(r, url.Values{}))
+}
+
+func TestWebhookDeduperMiddleware(t *testing.T) {
+	calls := 0
+	handler := NewWebhookDeduper(NewMemoryWebhookDedupeStore()).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "text/xml")
+			w.Write([]byte("<Response/>"))
+		}))
+
+	form := url.Values{"MessageSid": {"SM1"}, "SmsStatus": {"received"}}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newSignedRequest("token", "http://example.com/sms", form))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/xml", w.Header().Get("Content-Type"))
+		assert.Equal(t, "<Response/>", w.Body.String())
+	}
+
+	assert.Equal(t, 1, calls)
+
+	form.Set("MessageSid", "SM2")
+	handler.ServeHTTP(httptest.NewRecorder(), newSignedRequest("token", "http://example.com/sms", form))
+	assert.Equal(t, 2, calls)
+}
+
+func TestWebhookDeduperRetriesServerErrors(t *testing.T) {
+	calls := 0
+	handler := NewWebhookDeduper(NewMemoryWebhookDedupeStore()).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+	form := url.Values{"MessageSid": {"SM1"}, "MessageStatus": {"sent"}}
+	handler.ServeHTTP(httptest.NewRecorder(), newSignedRequest("token", "http://example.com/status", form))
+	handler.ServeHTTP(httptest.NewRecorder(), newSignedRequest("token", "http://example.com/status", form))
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoryWebhookDedupeStoreExpires(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryWebhookDedupeStore()
+	store.Now = func() time.Time { return now }
+
+	resp := &WebhookResponse{StatusCode: http.StatusNoContent}
+	assert.NoError(t, store.Put("key", resp, now.Add(time.Hour)))
+
+	got, err := store.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, resp, got)
+
+	now = now.Add(time.Hour)
+	got, err = store.Get("key")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Empty(t, store.responses)
+}
+
+func TestMemoryWebhookDedupeStoreSweepsOnPut(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryWebhookDedupeStore()
+	store.Now = func() time.Time { return now }
+
+	resp := &WebhookResponse{StatusCode: http.StatusNoContent}
+	assert.NoError(t, store.Put("a", resp, now.Add(time.Second)))
+
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, store.Put("b", resp, now.Add(time.Hour)))
+	assert.Len(t, store.responses, 2)
+
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, store.Put("c", resp, now.Add(time.Hour)))
+	assert.Len(t, store.responses, 2)
+	assert.NotContains(t, store.responses, "a")
+}
@@ -0,0 +1,164 @@
+package twilio
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedWebhook is a raw webhook request, including its signature, so
+// that it can be replayed against a handler.
+type RecordedWebhook struct {
+	Time   time.Time   `json:"time"`
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// WebhookRecorder records webhook requests as lines of JSON.
+type WebhookRecorder struct {
+	mu sync.Mutex
+	w  io.Writer
+
+	// BaseURL, when set, replaces the scheme and host of recorded requests.
+	// It should match the request validator's BaseURL.
+	BaseURL string
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// OnError is called when a request cannot be recorded. The request is
+	// still handled.
+	OnError func(error)
+}
+
+// NewWebhookRecorder will create a recorder that writes to w.
+func NewWebhookRecorder(w io.Writer) *WebhookRecorder {
+	return &WebhookRecorder{
+		w:   w,
+		Now: time.Now,
+	}
+}
+
+// Middleware records each request before passing it to the next handler.
+func (rec *WebhookRecorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := rec.Record(r)
+		if err != nil && rec.OnError != nil {
+			rec.OnError(err)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Record records a request. The body is restored so that it can be read
+// again.
+func (rec *WebhookRecorder) Record(r *http.Request) error {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	validator := &RequestValidator{BaseURL: rec.BaseURL}
+	b, err := json.Marshal(&RecordedWebhook{
+		Time:   rec.Now(),
+		Method: r.Method,
+		URL:    validator.URL(r),
+		Header: r.Header,
+		Body:   body,
+	})
+	if err != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	_, err = rec.w.Write(append(b, '\n'))
+	return err
+}
+
+// ReadRecordedWebhooks reads the webhooks written by a WebhookRecorder.
+func ReadRecordedWebhooks(r io.Reader) ([]*RecordedWebhook, error) {
+	var webhooks []*RecordedWebhook
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		webhook := &RecordedWebhook{}
+		err := json.Unmarshal(scanner.Bytes(), webhook)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Request returns a request that is identical to the recorded one, so that
+// its signature is still valid.
+func (webhook *RecordedWebhook) Request() (*http.Request, error) {
+	r, err := http.NewRequest(webhook.Method, webhook.URL, bytes.NewReader(webhook.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	r.RequestURI = r.URL.RequestURI()
+	for key, values := range webhook.Header {
+		r.Header[key] = append([]string{}, values...)
+	}
+
+	if r.URL.Scheme == "https" {
+		r.TLS = &tls.ConnectionState{}
+	}
+
+	return r, nil
+}
+
+// Replay sends the recorded request to the handler and returns its response.
+func (webhook *RecordedWebhook) Replay(handler http.Handler) (*WebhookResponse, error) {
+	r, err := webhook.Request()
+	if err != nil {
+		return nil, err
+	}
+
+	rec := newWebhookResponseRecorder(&discardResponseWriter{header: http.Header{}})
+	handler.ServeHTTP(rec, r)
+	return rec.response(), nil
+}
+
+// discardResponseWriter keeps a response's headers and discards the rest, so
+// that a recorder wrapping it captures the response without sending it.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(statusCode int) {}
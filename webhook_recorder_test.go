@@ -0,0 +1,64 @@
+// +build unit
+
+package twilio
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRecorderReplay(t *testing.T) {
+	var log bytes.Buffer
+	recorder := NewWebhookRecorder(&log)
+	recorder.Now = func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	validator := NewRequestValidator("token")
+	var bodies []string
+	handler := validator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, r.PostForm.Get("Body"))
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("ok"))
+	}))
+
+	form := url.Values{"MessageSid": {"SM1"}, "Body": {"Hello"}}
+	for _, requestURL := range []string{"http://example.com/sms", "https://example.com/sms?x=1"} {
+		r := newSignedRequest("token", requestURL, form)
+		if r.URL.Scheme == "https" {
+			r.Host = "example.com"
+		}
+
+		w := httptest.NewRecorder()
+		recorder.Middleware(handler).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	webhooks, err := ReadRecordedWebhooks(&log)
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 2)
+	assert.Equal(t, "http://example.com/sms", webhooks[0].URL)
+	assert.Equal(t, "https://example.com/sms?x=1", webhooks[1].URL)
+	assert.Equal(t, []byte(form.Encode()), webhooks[0].Body)
+
+	for _, webhook := range webhooks {
+		resp, err := webhook.Replay(handler)
+		assert.NoError(t, err)
+		assert.Equal(t, &WebhookResponse{
+			StatusCode:  http.StatusOK,
+			ContentType: "text/plain",
+			Body:        []byte("ok"),
+		}, resp)
+	}
+
+	assert.Equal(t, []string{"Hello", "Hello", "Hello", "Hello"}, bodies)
+
+	webhooks[0].Body = []byte("MessageSid=SM1&Body=Tampered")
+	resp, err := webhooks[0].Replay(handler)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
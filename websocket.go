@@ -0,0 +1,409 @@
+package twilio
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// The WebSocket opcodes from RFC 6455.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+)
+
+// The WebSocket close codes used when closing a connection.
+const (
+	wsCloseNormal        = 1000
+	wsCloseProtocolError = 1002
+	wsCloseTooBig        = 1009
+)
+
+// wsMaxMessageSize is the largest message that is read. Media Streams
+// messages are a few kilobytes.
+const wsMaxMessageSize = 1 << 20
+
+// wsGUID is appended to a client's key to compute the accept header.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var errWebSocketProtocol = errors.New("WebSocket protocol error")
+
+// wsConn is a minimal WebSocket connection that reads and writes whole
+// messages. Clients mask the frames they write and servers require frames
+// to be masked.
+type wsConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// upgradeWebSocket completes the opening handshake of a WebSocket request
+// and takes over its connection. An error response is written when the
+// request is not a valid WebSocket request.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(w, "Expected a WebSocket request", http.StatusBadRequest)
+		return nil, errWebSocketProtocol
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket is not supported", http.StatusInternalServerError)
+		return nil, errors.New("Response cannot be hijacked")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(rw,
+		"HTTP/1.1 101 Switching Protocols\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Sec-WebSocket-Accept: %s\r\n\r\n",
+		wsAccept(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, br: rw.Reader}, nil
+}
+
+// dialWebSocket opens a WebSocket connection to a ws, wss, http or https
+// URL.
+func dialWebSocket(rawurl string, header http.Header) (*wsConn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("Invalid WebSocket URL: %s", rawurl)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		if secure {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	var conn net.Conn
+	if secure {
+		conn, err = tls.Dial("tcp", host, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = net.Dial("tcp", host)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	_, err = rand.Read(b)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	key := base64.StdEncoding.EncodeToString(b)
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+
+	for name, values := range header {
+		req.Header[name] = append([]string{}, values...)
+	}
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	err = req.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		conn.Close()
+		return nil, &UnexpectedResponseError{
+			Expected:   http.StatusSwitchingProtocols,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	return &wsConn{conn: conn, br: br, client: true}, nil
+}
+
+// readMessage reads the next text or binary message, answering pings and
+// joining fragmented messages. It returns io.EOF once the peer has closed
+// the connection.
+func (c *wsConn) readMessage() (int, []byte, error) {
+	var opcode int
+	var message []byte
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsPing:
+			err = c.writeFrame(wsPong, payload)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			continue
+
+		case wsPong:
+			continue
+
+		case wsClose:
+			c.writeClose(wsCloseNormal)
+			c.conn.Close()
+			return 0, nil, io.EOF
+
+		case wsText, wsBinary:
+			if opcode != 0 {
+				return 0, nil, c.fail(wsCloseProtocolError)
+			}
+
+			opcode = op
+
+		case wsContinuation:
+			if opcode == 0 {
+				return 0, nil, c.fail(wsCloseProtocolError)
+			}
+
+		default:
+			return 0, nil, c.fail(wsCloseProtocolError)
+		}
+
+		if len(message)+len(payload) > wsMaxMessageSize {
+			return 0, nil, c.fail(wsCloseTooBig)
+		}
+
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	_, err := io.ReadFull(c.br, header[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+	if header[0]&0x70 != 0 || masked == c.client {
+		return false, 0, nil, c.fail(wsCloseProtocolError)
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var b [2]byte
+		_, err = io.ReadFull(c.br, b[:])
+		length = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		_, err = io.ReadFull(c.br, b[:])
+		length = binary.BigEndian.Uint64(b[:])
+	}
+
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if opcode >= wsClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(wsCloseProtocolError)
+	}
+
+	if length > wsMaxMessageSize {
+		return false, 0, nil, c.fail(wsCloseTooBig)
+	}
+
+	var mask [4]byte
+	if masked {
+		_, err = io.ReadFull(c.br, mask[:])
+		if err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.br, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a whole message or control frame.
+func (c *wsConn) writeFrame(opcode int, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return io.ErrClosedPipe
+	}
+
+	return c.writeFrameLocked(opcode, payload)
+}
+
+func (c *wsConn) writeFrameLocked(opcode int, payload []byte) error {
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|byte(opcode))
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, maskBit|126, byte(n>>8), byte(n))
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(n))
+		frame = append(frame, maskBit|127)
+		frame = append(frame, b[:]...)
+	}
+
+	if !c.client {
+		frame = append(frame, payload...)
+	} else {
+		var mask [4]byte
+		_, err := rand.Read(mask[:])
+		if err != nil {
+			return err
+		}
+
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// writeClose sends a close frame with the code. Nothing else can be written
+// afterwards.
+func (c *wsConn) writeClose(code int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	return c.writeFrameLocked(wsClose, []byte{byte(code >> 8), byte(code)})
+}
+
+// fail closes the connection after a protocol violation.
+func (c *wsConn) fail(code int) error {
+	c.writeClose(code)
+	c.conn.Close()
+	return errWebSocketProtocol
+}
+
+// close sends a normal close frame and closes the connection.
+func (c *wsConn) close() error {
+	err := c.writeClose(wsCloseNormal)
+	closeErr := c.conn.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+func wsAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken returns true when a comma separated header contains
+// the token, ignoring case.
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}